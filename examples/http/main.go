@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -12,8 +13,19 @@ func main() {
 	api := http.NewHttpClient(
 		http.Credentials("invalid", "invalid"),
 	)
-	currencyPair := "btcusd-perp"
-	market := "BTC/USD-PERP"
+
+	// order endpoints take the url symbol (e.g. "btcusd-perp"), derivatives ones the market name ("BTC/USD-PERP")
+	markets, err := api.MarketDirectory(context.Background())
+	if err != nil {
+		log.Panic(err)
+	}
+	perpetuals := markets.Markets(http.Perpetual)
+	if len(perpetuals) == 0 {
+		log.Panic("no perpetual markets")
+	}
+	currencyPair := perpetuals[0].MarketSymbol
+	market := perpetuals[0].Name
+	fmt.Printf("PERPETUAL MARKET: %+v\n", perpetuals[0])
 
 	// public endpoints
 	ticker1, err := api.V2Ticker(currencyPair)
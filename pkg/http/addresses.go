@@ -0,0 +1,113 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// DepositAddress returns the deposit address for the currency on the given network, checking against V2Currencies
+// that the currency can be deposited on it first. The network may be left empty for currencies available on a
+// single network only.
+func (c *HttpClient) DepositAddress(ctx context.Context, currency string, network string) (response V2CryptoAddressResponse, err error) {
+	catalogue, err := c.CurrencyCatalogue(ctx)
+	if err != nil {
+		return
+	}
+	network, err = depositNetwork(catalogue, currency, network)
+	if err != nil {
+		return
+	}
+	return c.V2CryptoAddressOnNetworkWithContext(ctx, strings.ToLower(currency), network)
+}
+
+// depositNetwork picks the network to deposit the currency on, the given one if set.
+func depositNetwork(catalogue CurrencyCatalogue, currency string, network string) (string, error) {
+	info, exists := catalogue.Currency(currency)
+	if !exists {
+		return "", fmt.Errorf("unknown currency: %s", currency)
+	}
+	enabled := info.DepositNetworks()
+	switch {
+	case network != "":
+		if n, exists := info.Network(network); exists && bool(n.Deposit) {
+			return n.Network, nil
+		}
+		return "", fmt.Errorf("%s deposits are not available on network %s, only on: %s", currency, network, strings.Join(enabled, ", "))
+	case len(info.Networks) == 0 && bool(info.Deposit):
+		return "", nil // no network information, let the exchange pick the default
+	case len(enabled) == 1:
+		return enabled[0], nil
+	case len(enabled) > 1:
+		return "", fmt.Errorf("%s is available on multiple networks, pick one of: %s", currency, strings.Join(enabled, ", "))
+	default:
+		return "", fmt.Errorf("%s deposits are disabled", currency)
+	}
+}
+
+const base58Chars = "1-9A-HJ-NP-Za-km-z"
+
+var (
+	evmAddress     = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	addressFormats = map[string][]*regexp.Regexp{
+		"bitcoin": {
+			regexp.MustCompile(`^[13][` + base58Chars + `]{25,34}$`),
+			regexp.MustCompile(`^(bc1[02-9ac-hj-np-z]{11,71}|BC1[02-9AC-HJ-NP-Z]{11,71})$`),
+		},
+		"litecoin": {
+			regexp.MustCompile(`^[LM3][` + base58Chars + `]{25,34}$`),
+			regexp.MustCompile(`^(ltc1[02-9ac-hj-np-z]{11,71}|LTC1[02-9AC-HJ-NP-Z]{11,71})$`),
+		},
+		"ethereum": {evmAddress},
+		"polygon":  {evmAddress},
+		"arbitrum": {evmAddress},
+		"optimism": {evmAddress},
+		"base":     {evmAddress},
+		"ripple":   {regexp.MustCompile(`^r[` + base58Chars + `]{24,34}$`)},
+		"stellar":  {regexp.MustCompile(`^G[A-Z2-7]{55}$`)},
+		"tron":     {regexp.MustCompile(`^T[` + base58Chars + `]{33}$`)},
+		"solana":   {regexp.MustCompile(`^[` + base58Chars + `]{32,44}$`)},
+	}
+	// networks of currencies that are usually withdrawn without naming the network
+	defaultNetworks = map[string]string{
+		"btc": "bitcoin",
+		"ltc": "litecoin",
+		"eth": "ethereum",
+		"xrp": "ripple",
+		"xlm": "stellar",
+		"sol": "solana",
+	}
+)
+
+// ValidateAddress rejects obviously malformed addresses, e.g. ones containing whitespace or, on networks whose
+// address format is known, ones not matching it. It does not verify checksums, a nil error doesn't guarantee the
+// exchange accepts the address. The network may be left empty for the currency's default network.
+func ValidateAddress(currency string, network string, address string) error {
+	if address == "" {
+		return fmt.Errorf("%w: empty", ErrInvalidAddress)
+	}
+	if len(address) > 128 {
+		return fmt.Errorf("%w: too long", ErrInvalidAddress)
+	}
+	for _, r := range address {
+		if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return fmt.Errorf("%w: %q contains whitespace or control characters", ErrInvalidAddress, address)
+		}
+	}
+
+	if network == "" {
+		network = defaultNetworks[strings.ToLower(currency)]
+	}
+	formats, known := addressFormats[strings.ToLower(network)]
+	if !known {
+		return nil
+	}
+	for _, format := range formats {
+		if format.MatchString(address) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q is not a %s address", ErrInvalidAddress, address, network)
+}
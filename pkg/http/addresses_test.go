@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddress(t *testing.T) {
+	cases := []struct {
+		currency, network, address string
+		valid                      bool
+	}{
+		{"btc", "", "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", true},
+		{"btc", "", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", true},
+		{"btc", "", "0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe", false},
+		{"btc", "", "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdO", false}, // O isn't in the bech32 charset
+		{"usdc", "polygon", "0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe", true},
+		{"usdc", "polygon", "0xde0B295669a9FD93d5F28D9Ec85E40f4cb697B", false},
+		{"usdt", "tron", "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", true},
+		{"xrp", "", "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", true},
+		{"xrp", "", "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAY0", false}, // 0 isn't in the base58 charset
+		{"newcoin", "", "anything-goes_123", true},
+		{"newcoin", "", "has space", false},
+		{"newcoin", "", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.currency+" "+tc.address, func(t *testing.T) {
+			err := ValidateAddress(tc.currency, tc.network, tc.address)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidAddress)
+			}
+		})
+	}
+}
+
+func TestDepositAddress(t *testing.T) {
+	var forms []url.Values
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		if r.URL.Path == "/v2/currencies/" {
+			return 200, `[
+				{"currency": "USDC", "deposit": "Enabled", "networks": [
+					{"network": "ethereum", "deposit": "Enabled", "withdrawal": "Enabled"},
+					{"network": "polygon", "deposit": "Enabled", "withdrawal": "Enabled"},
+					{"network": "solana", "deposit": "Disabled", "withdrawal": "Enabled"}
+				]},
+				{"currency": "XRP", "deposit": "Enabled", "networks": [{"network": "ripple", "deposit": "Enabled", "withdrawal": "Enabled"}]},
+				{"currency": "EUR", "deposit": "Enabled"}
+			]`
+		}
+		_ = r.ParseForm()
+		forms = append(forms, r.PostForm)
+		if r.URL.Path == "/v2/xrp_address/" {
+			return 200, `{"address": "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", "destination_tag": 89123}`
+		}
+		return 200, `{"address": "0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe", "network": "polygon"}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+	ctx := context.Background()
+
+	address, err := c.DepositAddress(ctx, "USDC", "polygon")
+	assert.NoError(t, err)
+	assert.Equal(t, "polygon", address.Network)
+	assert.Equal(t, "polygon", forms[0].Get("network"))
+
+	// the only network is picked implicitly
+	address, err = c.DepositAddress(ctx, "xrp", "")
+	assert.NoError(t, err)
+	assert.Equal(t, StringInt(89123), address.DestinationTag)
+	assert.Equal(t, "ripple", address.Network)
+
+	_, err = c.DepositAddress(ctx, "usdc", "")
+	assert.ErrorContains(t, err, "pick one of: ethereum, polygon")
+	_, err = c.DepositAddress(ctx, "usdc", "solana")
+	assert.ErrorContains(t, err, "not available on network solana")
+	_, err = c.DepositAddress(ctx, "doge", "")
+	assert.ErrorContains(t, err, "unknown currency")
+	assert.Len(t, forms, 2)
+}
@@ -3,6 +3,8 @@ package http
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"net/url"
 	"time"
 
@@ -11,15 +13,32 @@ import (
 
 const bitstampHttpApiUrl = "https://www.bitstamp.net/api"
 
+// defaults for the underlying *http.Client; a single one is shared by all requests of an HttpClient
+// so that connections to the API get reused.
+const (
+	defaultRequestTimeout      = 30 * time.Second
+	defaultDialTimeout         = 10 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultMaxIdleConnsPerHost = 16
+)
+
 type httpClientConfig struct {
 	domain             url.URL
+	httpClient         *http.Client
+	rateLimiter        *RateLimiter
+	retryPolicy        *RetryPolicy
+	middlewares        []Middleware
+	pairRegistry       *PairRegistry
 	apiKey             string
 	apiSecret          string
+	subAccount         *int64
 	nonceGenerator     func() string
 	timestampGenerator func() string
 	// have client implicitly round input prices/amounts to correct number of decimal places.
 	// used solely for consumers' convenience and will probably be removed at some point.
-	autoRounding bool
+	autoRounding   bool
+	roundingPolicy RoundingPolicy
 }
 
 func defaultHttpClientConfig() *httpClientConfig {
@@ -29,8 +48,30 @@ func defaultHttpClientConfig() *httpClientConfig {
 	}
 	return &httpClientConfig{
 		domain:             *domain,
+		httpClient:         defaultHttpClient(),
 		nonceGenerator:     defaultNonce,
 		timestampGenerator: timestamp,
+		roundingPolicy:     StingyRounding,
+	}
+}
+
+func defaultHttpClient() *http.Client {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   defaultMaxIdleConnsPerHost,
+		IdleConnTimeout:       defaultIdleConnTimeout,
+		TLSHandshakeTimeout:   defaultTLSHandshakeTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   defaultRequestTimeout,
 	}
 }
 
@@ -46,6 +87,57 @@ func UrlDomain(rawDomain string) HttpOption {
 	}
 }
 
+// CustomHttpClient makes HttpClient send all (public and signed) requests through the given client.
+// Useful for custom timeouts, proxies, TLS settings or pointing the client at a test server.
+func CustomHttpClient(client *http.Client) HttpOption {
+	return func(config *httpClientConfig) {
+		config.httpClient = client
+	}
+}
+
+// CustomTransport keeps the configured *http.Client settings (e.g. timeout), but replaces its transport.
+func CustomTransport(transport http.RoundTripper) HttpOption {
+	return func(config *httpClientConfig) {
+		client := *config.httpClient
+		client.Transport = transport
+		config.httpClient = &client
+	}
+}
+
+// RateLimiting throttles all requests through the given limiter. Pass the same limiter to every HttpClient
+// using the same API key.
+func RateLimiting(limiter *RateLimiter) HttpOption {
+	return func(config *httpClientConfig) {
+		config.rateLimiter = limiter
+	}
+}
+
+// Retries enables retrying of failed requests according to the given policy.
+func Retries(policy RetryPolicy) HttpOption {
+	if policy.Retryable == nil {
+		policy.Retryable = DefaultRetryable
+	}
+	return func(config *httpClientConfig) {
+		config.retryPolicy = &policy
+	}
+}
+
+// Middlewares adds middlewares around every request. They are run in the given order, i.e. the first one is
+// the outermost. May be used multiple times.
+func Middlewares(middlewares ...Middleware) HttpOption {
+	return func(config *httpClientConfig) {
+		config.middlewares = append(config.middlewares, middlewares...)
+	}
+}
+
+// TradingPairs makes the client validate and round orders using the given registry instead of one of its own.
+// Pass the same registry to every HttpClient to load trading pairs info only once.
+func TradingPairs(registry *PairRegistry) HttpOption {
+	return func(config *httpClientConfig) {
+		config.pairRegistry = registry
+	}
+}
+
 func Credentials(apiKey string, apiSecret string) HttpOption {
 	return func(config *httpClientConfig) {
 		config.apiKey = apiKey
@@ -53,12 +145,30 @@ func Credentials(apiKey string, apiSecret string) HttpOption {
 	}
 }
 
+// OnBehalfOfSubAccount makes a client using the main account's credentials act on the given sub-account where the
+// API supports it, currently V2TransferToMain.
+func OnBehalfOfSubAccount(subAccountId int64) HttpOption {
+	return func(config *httpClientConfig) {
+		config.subAccount = &subAccountId
+	}
+}
+
+// AutoRoundingEnabled makes the client round order amounts and prices to the pair's decimals and tick size,
+// following StingyRounding unless configured otherwise with the Rounding option.
 func AutoRoundingEnabled() HttpOption {
 	return func(config *httpClientConfig) {
 		config.autoRounding = true
 	}
 }
 
+// Rounding enables auto rounding (see AutoRoundingEnabled) with the given policy.
+func Rounding(policy RoundingPolicy) HttpOption {
+	return func(config *httpClientConfig) {
+		config.autoRounding = true
+		config.roundingPolicy = policy
+	}
+}
+
 // 10x slower the than previous `fmt.Sprintf("%d", time.Now().UnixNano())`, should I worry?
 func defaultNonce() string {
 	return uuid.NewString()
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,3 +19,46 @@ func TestDefaultTimestamp(t *testing.T) {
 	ts := timestamp()
 	assert.Regexp(t, `^\d{13}$`, ts)
 }
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestDefaultHttpClient(t *testing.T) {
+	c := NewHttpClient()
+	assert.Equal(t, defaultRequestTimeout, c.httpClient.Timeout)
+	assert.IsType(t, &http.Transport{}, c.httpClient.Transport)
+}
+
+func TestCustomTransport(t *testing.T) {
+	var seen []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		seen = append(seen, req.Method+" "+req.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"buy": "1.1", "sell": "1.2"}`)),
+		}, nil
+	})
+
+	c := NewHttpClient(CustomTransport(transport))
+	assert.Equal(t, defaultRequestTimeout, c.httpClient.Timeout)
+
+	resp, err := c.V2EurUsd()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1", resp.Buy.String())
+
+	// signature verification fails on our fake response, but the request must still go through the transport
+	_, err = c.V2AccountBalances()
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"GET /api/v2/eur_usd/", "POST /api/v2/account_balances/"}, seen)
+}
+
+func TestCustomHttpClient(t *testing.T) {
+	client := &http.Client{}
+	c := NewHttpClient(CustomHttpClient(client))
+	assert.Same(t, client, c.httpClient)
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+//
+// Earn (staking and lending)
+//
+
+type EarnType string
+
+const (
+	Staking EarnType = "STAKING"
+	Lending EarnType = "LENDING"
+)
+
+type V2EarnProduct struct {
+	Currency             string          `json:"currency"`
+	Type                 EarnType        `json:"type"`
+	EstimatedAnnualYield decimal.Decimal `json:"estimated_annual_yield"`
+	MinimumAmount        decimal.Decimal `json:"minimum_amount"`
+}
+
+func (c *HttpClient) V2EarnProducts() (response []V2EarnProduct, err error) {
+	return c.V2EarnProductsWithContext(context.Background())
+}
+
+func (c *HttpClient) V2EarnProductsWithContext(ctx context.Context) (response []V2EarnProduct, err error) {
+	urlPath := "/v2/earn/products/"
+
+	err = c.authenticatedJsonRequest(ctx, "V2EarnProducts", &response, "GET", urlPath, nil, nil)
+	if err != nil {
+		return
+	}
+
+	return response, nil
+}
+
+type V2EarnSubscription struct {
+	Currency             string          `json:"currency"`
+	Type                 EarnType        `json:"type"`
+	Amount               decimal.Decimal `json:"amount"`
+	AmountSpendable      decimal.Decimal `json:"amount_spendable"`
+	AvailableAmount      decimal.Decimal `json:"available_amount"`
+	EstimatedAnnualYield decimal.Decimal `json:"estimated_annual_yield"`
+	DistributedRewards   decimal.Decimal `json:"distributed_rewards"`
+}
+
+func (c *HttpClient) V2EarnSubscriptions() (response []V2EarnSubscription, err error) {
+	return c.V2EarnSubscriptionsWithContext(context.Background())
+}
+
+func (c *HttpClient) V2EarnSubscriptionsWithContext(ctx context.Context) (response []V2EarnSubscription, err error) {
+	urlPath := "/v2/earn/subscriptions/"
+
+	err = c.authenticatedJsonRequest(ctx, "V2EarnSubscriptions", &response, "GET", urlPath, nil, nil)
+	if err != nil {
+		return
+	}
+
+	return response, nil
+}
+
+type V2EarnSubscribeRequest struct {
+	Currency string          `json:"currency"`
+	EarnType EarnType        `json:"earn_type"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+type V2EarnSubscribeResponse struct {
+	Currency string          `json:"currency"`
+	EarnType EarnType        `json:"earn_type"`
+	Amount   decimal.Decimal `json:"amount"`
+}
+
+func newEarnSubscribeRequest(currency string, earnType EarnType, amount decimal.Decimal) (request V2EarnSubscribeRequest, err error) {
+	if currency == "" {
+		err = errors.New("currency is required")
+		return
+	}
+	if earnType != Staking && earnType != Lending {
+		err = fmt.Errorf("invalid earn type: %q", earnType)
+		return
+	}
+	if !amount.IsPositive() {
+		err = fmt.Errorf("amount must be positive: %s", amount)
+		return
+	}
+	return V2EarnSubscribeRequest{
+		Currency: strings.ToUpper(currency),
+		EarnType: earnType,
+		Amount:   amount,
+	}, nil
+}
+
+func (c *HttpClient) V2EarnSubscribe(currency string, earnType EarnType, amount decimal.Decimal) (response V2EarnSubscribeResponse, err error) {
+	return c.V2EarnSubscribeWithContext(context.Background(), currency, earnType, amount)
+}
+
+func (c *HttpClient) V2EarnSubscribeWithContext(ctx context.Context, currency string, earnType EarnType, amount decimal.Decimal) (response V2EarnSubscribeResponse, err error) {
+	urlPath := "/v2/earn/subscribe/"
+	requestPayload, err := newEarnSubscribeRequest(currency, earnType, amount)
+	if err != nil {
+		return
+	}
+
+	err = c.authenticatedJsonRequest(ctx, "V2EarnSubscribe", &response, "POST", urlPath, nil, requestPayload)
+	if err != nil {
+		return
+	}
+
+	return response, nil
+}
+
+// V2EarnUnsubscribe moves the amount back to the spendable balance. Depending on the currency, unstaked funds
+// might only become available after an unbonding period.
+func (c *HttpClient) V2EarnUnsubscribe(currency string, earnType EarnType, amount decimal.Decimal) (response V2EarnSubscribeResponse, err error) {
+	return c.V2EarnUnsubscribeWithContext(context.Background(), currency, earnType, amount)
+}
+
+func (c *HttpClient) V2EarnUnsubscribeWithContext(ctx context.Context, currency string, earnType EarnType, amount decimal.Decimal) (response V2EarnSubscribeResponse, err error) {
+	urlPath := "/v2/earn/unsubscribe/"
+	requestPayload, err := newEarnSubscribeRequest(currency, earnType, amount)
+	if err != nil {
+		return
+	}
+
+	err = c.authenticatedJsonRequest(ctx, "V2EarnUnsubscribe", &response, "POST", urlPath, nil, requestPayload)
+	if err != nil {
+		return
+	}
+
+	return response, nil
+}
+
+type EarnTransactionType string
+
+const (
+	EarnSubscribe   EarnTransactionType = "SUBSCRIBE"
+	EarnUnsubscribe EarnTransactionType = "UNSUBSCRIBE"
+	EarnReward      EarnTransactionType = "REWARD"
+)
+
+type V2EarnTransaction struct {
+	Datetime      StringDatetime      `json:"datetime"`
+	Type          EarnTransactionType `json:"type"`
+	Status        string              `json:"status"`
+	Currency      string              `json:"currency"`
+	Amount        decimal.Decimal     `json:"amount"`
+	Value         decimal.Decimal     `json:"value"` // amount in QuoteCurrency at the time of the transaction
+	QuoteCurrency string              `json:"quote_currency"`
+}
+
+// V2EarnTransactions lists subscriptions, unsubscriptions and rewards (see EarnReward), newest first.
+func (c *HttpClient) V2EarnTransactions(currency *string, quoteCurrency *string, offset *int64, limit *int64) (response []V2EarnTransaction, err error) {
+	return c.V2EarnTransactionsWithContext(context.Background(), currency, quoteCurrency, offset, limit)
+}
+
+func (c *HttpClient) V2EarnTransactionsWithContext(ctx context.Context, currency *string, quoteCurrency *string, offset *int64, limit *int64) (response []V2EarnTransaction, err error) {
+	if offset == nil {
+		offsetValue := int64(0)
+		offset = &offsetValue
+	}
+	if limit == nil {
+		limitValue := int64(100)
+		limit = &limitValue
+	}
+	if *limit > 1000 {
+		err = errors.New("invalid limit")
+		return
+	}
+	urlPath := "/v2/earn/transactions/"
+	urlParams := make(url.Values)
+	urlParams.Set("offset", strconv.FormatInt(*offset, 10))
+	urlParams.Set("limit", strconv.FormatInt(*limit, 10))
+	if currency != nil {
+		urlParams.Set("currency", strings.ToUpper(*currency))
+	}
+	if quoteCurrency != nil {
+		urlParams.Set("quote_currency", strings.ToUpper(*quoteCurrency))
+	}
+
+	err = c.authenticatedJsonRequest(ctx, "V2EarnTransactions", &response, "GET", urlPath, &urlParams, nil)
+	if err != nil {
+		return
+	}
+
+	return response, nil
+}
+
+// EarnRewards returns the rewards among one page of V2EarnTransactions. Pages are counted in transactions of all
+// types, so a page without rewards doesn't mean there are none left; more reports whether there might be another
+// page.
+func (c *HttpClient) EarnRewards(ctx context.Context, currency *string, offset int64, limit int64) (rewards []V2EarnTransaction, more bool, err error) {
+	transactions, err := c.V2EarnTransactionsWithContext(ctx, currency, nil, &offset, &limit)
+	if err != nil {
+		return
+	}
+	for _, transaction := range transactions {
+		if transaction.Type == EarnReward {
+			rewards = append(rewards, transaction)
+		}
+	}
+	return rewards, int64(len(transactions)) == limit, nil
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV2EarnSubscribe(t *testing.T) {
+	var payloads []map[string]interface{}
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		_ = json.Unmarshal(body, &payload)
+		payloads = append(payloads, payload)
+		return 200, string(body)
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+	resp, err := c.V2EarnSubscribe("eth", Staking, decimal.RequireFromString("1.5"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ETH", resp.Currency)
+	assert.True(t, decimal.RequireFromString("1.5").Equal(resp.Amount))
+	_, err = c.V2EarnUnsubscribe("sol", Staking, decimal.NewFromInt(10))
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"currency": "ETH", "earn_type": "STAKING", "amount": "1.5"},
+		{"currency": "SOL", "earn_type": "STAKING", "amount": "10"},
+	}, payloads)
+
+	_, err = c.V2EarnSubscribe("eth", "SAVINGS", decimal.NewFromInt(1))
+	assert.ErrorContains(t, err, "invalid earn type")
+	_, err = c.V2EarnSubscribe("eth", Staking, decimal.Zero)
+	assert.ErrorContains(t, err, "amount must be positive")
+	assert.Len(t, payloads, 2)
+}
+
+func TestEarnRewards(t *testing.T) {
+	var query string
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		query = r.URL.RawQuery
+		return 200, `[
+			{"datetime": "2024-03-01 00:00:00", "type": "REWARD", "status": "COMPLETED", "currency": "ETH", "amount": "0.00012", "value": "0.41", "quote_currency": "USD"},
+			{"datetime": "2024-02-28T12:30:00Z", "type": "SUBSCRIBE", "status": "COMPLETED", "currency": "ETH", "amount": "1.5", "value": "5100", "quote_currency": "USD"}
+		]`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+	currency := "eth"
+
+	rewards, more, err := c.EarnRewards(context.Background(), &currency, 20, 2)
+	assert.NoError(t, err)
+	assert.True(t, more)
+	assert.Equal(t, "currency=ETH&limit=2&offset=20", query)
+	if assert.Len(t, rewards, 1) {
+		assert.True(t, decimal.RequireFromString("0.00012").Equal(rewards[0].Amount))
+		assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), rewards[0].Datetime.Time())
+	}
+
+	_, more, err = c.EarnRewards(context.Background(), nil, 0, 10)
+	assert.NoError(t, err)
+	assert.False(t, more)
+}
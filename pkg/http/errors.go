@@ -0,0 +1,214 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors for conditions consumers commonly need to branch on. An *ApiError matches (at most) one of
+// these via errors.Is, e.g.:
+//
+//	if errors.Is(err, http.ErrInsufficientBalance) { ... }
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrInvalidNonce        = errors.New("invalid nonce")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrMaintenance         = errors.New("service unavailable")
+	ErrInvalidAddress      = errors.New("invalid address")
+	ErrWithdrawalLimit     = errors.New("withdrawal limit exceeded")
+)
+
+// ApiError is returned for every error response of the API, be it a non-2xx HTTP status or a 200 response
+// with `"status": "error"` in its body.
+type ApiError struct {
+	StatusCode   int                 // HTTP status code
+	Status       string              // HTTP status line, e.g. "403 Forbidden"
+	Code         string              // Bitstamp error code (e.g. API0004), if present
+	Reasons      []string            // general reasons (the `__all__` key or a plain string reason)
+	FieldReasons map[string][]string // field-level reasons, keyed by request field name
+	Url          string              // request URL
+	Content      string              // raw response body
+}
+
+func (e *ApiError) Error() string {
+	var parts []string
+	if e.Code != "" {
+		parts = append(parts, e.Code)
+	}
+	if len(e.Reasons) > 0 {
+		parts = append(parts, strings.Join(e.Reasons, "; "))
+	}
+	fields := make([]string, 0, len(e.FieldReasons))
+	for field := range e.FieldReasons {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", field, strings.Join(e.FieldReasons[field], "; ")))
+	}
+	if len(parts) == 0 {
+		if e.Content != "" {
+			parts = append(parts, e.Content)
+		} else {
+			parts = append(parts, e.Status)
+		}
+	}
+	return fmt.Sprintf("%s (%d %s)", strings.Join(parts, " "), e.StatusCode, e.Url)
+}
+
+// Is makes errors.Is(err, ErrXxx) work for the sentinel errors above.
+func (e *ApiError) Is(target error) bool {
+	return target != nil && e.kind() == target
+}
+
+// kind classifies the error into one of the sentinel errors (or nil). Bitstamp is not very consistent in the
+// codes it returns, hence we also look at HTTP status codes and reason messages.
+func (e *ApiError) kind() error {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrMaintenance
+	}
+
+	if kind, exists := apiErrorCodes[e.Code]; exists {
+		return kind
+	}
+
+	messages := strings.ToLower(strings.Join(e.Reasons, " "))
+	for _, reasons := range e.FieldReasons {
+		messages += " " + strings.ToLower(strings.Join(reasons, " "))
+	}
+	for _, m := range apiErrorMessages {
+		if strings.Contains(messages, m.substring) {
+			return m.kind
+		}
+	}
+	return nil
+}
+
+var apiErrorCodes = map[string]error{
+	"API0004": ErrInvalidNonce,
+}
+
+var apiErrorMessages = []struct {
+	substring string
+	kind      error
+}{
+	{"nonce", ErrInvalidNonce},
+	{"order not found", ErrOrderNotFound},
+	{"you have only", ErrInsufficientBalance},
+	{"insufficient", ErrInsufficientBalance},
+	{"not enough", ErrInsufficientBalance},
+	{"rate limit", ErrRateLimited},
+	{"too many requests", ErrRateLimited},
+	{"maintenance", ErrMaintenance},
+	{"invalid address", ErrInvalidAddress},
+	{"address is not valid", ErrInvalidAddress},
+	{"withdrawal limit", ErrWithdrawalLimit},
+}
+
+// newApiError constructs an *ApiError from an HTTP error response. Body parsing is best-effort: whatever cannot
+// be interpreted is still available in Content.
+func newApiError(statusCode int, status string, url_ string, body []byte) *ApiError {
+	e := &ApiError{
+		StatusCode: statusCode,
+		Status:     status,
+		Url:        url_,
+		Content:    string(body),
+	}
+
+	var raw struct {
+		Code   interface{}     `json:"code"`
+		Reason json.RawMessage `json:"reason"`
+		Error  string          `json:"error"`
+		Errors []struct {
+			Code    string `json:"code"`
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return e
+	}
+
+	if raw.Code != nil {
+		e.Code = fmt.Sprint(raw.Code)
+	}
+	if raw.Error != "" {
+		e.Reasons = append(e.Reasons, raw.Error)
+	}
+	e.addReason(raw.Reason)
+	for _, fieldErr := range raw.Errors {
+		if e.Code == "" {
+			e.Code = fieldErr.Code
+		}
+		if fieldErr.Field == "" {
+			e.Reasons = append(e.Reasons, fieldErr.Message)
+		} else {
+			e.addFieldReasons(fieldErr.Field, fieldErr.Message)
+		}
+	}
+
+	return e
+}
+
+// reason is either a plain string or a {"__all__": [...], "<field>": [...]} object
+func (e *ApiError) addReason(reason json.RawMessage) {
+	if len(reason) == 0 {
+		return
+	}
+
+	var text string
+	if err := json.Unmarshal(reason, &text); err == nil {
+		if text != "" {
+			e.Reasons = append(e.Reasons, text)
+		}
+		return
+	}
+
+	var byField map[string]json.RawMessage
+	if err := json.Unmarshal(reason, &byField); err != nil {
+		e.Reasons = append(e.Reasons, string(reason))
+		return
+	}
+	for field, val := range byField {
+		var messages []string
+		if err := json.Unmarshal(val, &messages); err != nil {
+			messages = []string{strings.Trim(string(val), `"`)}
+		}
+		if field == "__all__" {
+			e.Reasons = append(e.Reasons, messages...)
+		} else {
+			e.addFieldReasons(field, messages...)
+		}
+	}
+}
+
+func (e *ApiError) addFieldReasons(field string, messages ...string) {
+	if e.FieldReasons == nil {
+		e.FieldReasons = make(map[string][]string)
+	}
+	e.FieldReasons[field] = append(e.FieldReasons[field], messages...)
+}
+
+// responseError checks a successful (2xx) response body for an embedded error, i.e. `"status": "error"` or
+// a non-empty `"error"` field, which some endpoints return instead of a proper HTTP error code.
+func responseError(statusCode int, status string, url_ string, body []byte) *ApiError {
+	var probe struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return nil // not an object (e.g. a list), so not an error either
+	}
+	if probe.Status != "error" && probe.Error == "" {
+		return nil
+	}
+	return newApiError(statusCode, status, url_, body)
+}
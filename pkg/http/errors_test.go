@@ -0,0 +1,40 @@
+package http
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewApiError(t *testing.T) {
+	e := newApiError(400, "400 Bad Request", "https://www.bitstamp.net/api/v2/buy/btcusd/",
+		[]byte(`{"status": "error", "reason": {"__all__": ["Price is more than 20% below market price."], "amount": ["Ensure this value is greater than or equal to 1E-8."]}}`))
+
+	assert.Equal(t, []string{"Price is more than 20% below market price."}, e.Reasons)
+	assert.Equal(t, map[string][]string{"amount": {"Ensure this value is greater than or equal to 1E-8."}}, e.FieldReasons)
+	assert.Equal(t, "Price is more than 20% below market price. amount: Ensure this value is greater than or equal to 1E-8. (400 https://www.bitstamp.net/api/v2/buy/btcusd/)", e.Error())
+	assert.False(t, errors.Is(e, ErrInsufficientBalance))
+
+	e = newApiError(400, "400 Bad Request", "", []byte(`{"errors": [{"code": "E001", "field": "new_amount", "message": "Insufficient collateral"}]}`))
+	assert.Equal(t, "E001", e.Code)
+	assert.Equal(t, []string{"Insufficient collateral"}, e.FieldReasons["new_amount"])
+	assert.ErrorIs(t, e, ErrInsufficientBalance)
+
+	e = newApiError(200, "200 OK", "", []byte(`{"status": "error", "reason": {"address": ["Invalid address."]}}`))
+	assert.ErrorIs(t, e, ErrInvalidAddress)
+
+	// address fields aren't necessarily crypto addresses, e.g. the beneficiary's address of bank withdrawals
+	e = newApiError(200, "200 OK", "", []byte(`{"status": "error", "reason": {"address": ["This field is required."]}}`))
+	assert.NotErrorIs(t, e, ErrInvalidAddress)
+
+	e = newApiError(502, "502 Bad Gateway", "", []byte("not json"))
+	assert.Equal(t, "not json", e.Content)
+	assert.Nil(t, e.kind())
+}
+
+func TestResponseError(t *testing.T) {
+	assert.Nil(t, responseError(200, "200 OK", "", []byte(`[{"status": "error"}]`)))
+	assert.Nil(t, responseError(200, "200 OK", "", []byte(`{"status": "Open", "id": 1}`)))
+	assert.NotNil(t, responseError(200, "200 OK", "", []byte(`{"error": "Order not found"}`)))
+}
@@ -2,11 +2,11 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -38,24 +38,40 @@ func urlMerge(baseUrl url.URL, urlPath string, queryParams *url.Values) string {
 	return baseUrl.String()
 }
 
-func validateCurrencyPair(currencyPair string) error {
-	if _, exists := roundings[currencyPair]; exists {
+func (c *HttpClient) validateCurrencyPair(ctx context.Context, currencyPair string) error {
+	if _, exists := c.pairRegistry.Pair(ctx, currencyPair); exists {
 		return nil
 	} else {
 		return fmt.Errorf("unknown currency pair: %s", currencyPair)
 	}
 }
 
-// GetRequestError is a custom error type that makes for somewhat nicer logic with non-200 codes returned.
-type GetRequestError struct {
-	Code    int
-	Content string
-	Status  string
-	Url     string
+// RequestInfo describes an outgoing API request.
+type RequestInfo struct {
+	Endpoint    string // name of the HttpClient method, e.g. V2OpenOrders
+	Method      string
+	Path        string // path relative to the API domain, e.g. /v2/open_orders/all/
+	Url         string
+	Signed      bool
+	ContentType string
+	Payload     string // form-encoded or JSON request body of signed requests
 }
 
-func (e *GetRequestError) Error() string {
-	return fmt.Sprintf("%s (%s)", e.Status, e.Url)
+// contextError replaces a transport error with the context's own error (context.Canceled or
+// context.DeadlineExceeded) when the request failed because ctx was done, so callers can tell
+// cancellation apart from network failures with a plain errors.Is check.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+func (c *HttpClient) waitRateLimit(ctx context.Context, signed bool) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+	return c.rateLimiter.Wait(ctx, signed)
 }
 
 // HttpClient implements the HTTP (REST) API endpoints.
@@ -68,37 +84,63 @@ func NewHttpClient(options ...HttpOption) *HttpClient {
 	for _, option := range options {
 		option(config)
 	}
-	return &HttpClient{config}
+	c := &HttpClient{httpClientConfig: config}
+	if c.pairRegistry == nil {
+		c.pairRegistry = NewPairRegistry(c)
+	}
+	return c
 }
 
-func (c *HttpClient) getRequest(responseObject interface{}, urlPath string, queryParams *url.Values) (err error) {
-	url_ := urlMerge(c.domain, urlPath, queryParams)
+// Pairs returns the registry of trading pairs used for validating and rounding orders.
+func (c *HttpClient) Pairs() *PairRegistry {
+	return c.pairRegistry
+}
 
-	resp, err := http.Get(url_)
-	if err != nil {
-		return
+func (c *HttpClient) getRequest(ctx context.Context, endpoint string, responseObject interface{}, urlPath string, queryParams *url.Values) (err error) {
+	request := RequestInfo{
+		Endpoint: endpoint,
+		Method:   http.MethodGet,
+		Path:     urlPath,
+		Url:      urlMerge(c.domain, urlPath, queryParams),
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	return c.withRetries(ctx, request, func() error {
+		return c.doGetRequest(ctx, responseObject, request)
+	})
+}
+
+func (c *HttpClient) doGetRequest(ctx context.Context, responseObject interface{}, request RequestInfo) (err error) {
+	if err = c.waitRateLimit(ctx, false); err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, request.Url, nil)
 	if err != nil {
 		return
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return &GetRequestError{
-			Code:    resp.StatusCode,
-			Content: string(respBody),
-			Status:  resp.Status,
-			Url:     url_,
+	call := &Call{Request: request, Header: req.Header}
+	return c.roundTrip(ctx, call, func(ctx context.Context) error {
+		resp, err := c.httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return contextError(ctx, err)
 		}
-	}
+		defer resp.Body.Close()
+		call.StatusCode = resp.StatusCode
 
-	err = json.Unmarshal(respBody, responseObject)
-	return
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return contextError(ctx, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return newApiError(resp.StatusCode, resp.Status, request.Url, respBody)
+		}
+
+		return json.Unmarshal(respBody, responseObject)
+	})
 }
 
-func (c *HttpClient) authenticatedFormRequest(responseObject interface{}, method string, urlPath string, queryParams *url.Values, formQueryParams map[string]string) (err error) {
+func (c *HttpClient) authenticatedFormRequest(ctx context.Context, endpoint string, responseObject interface{}, method string, urlPath string, queryParams *url.Values, formQueryParams map[string]string) (err error) {
 	contentType := "application/x-www-form-urlencoded"
 	var payloadString string
 	if formQueryParams != nil {
@@ -109,11 +151,11 @@ func (c *HttpClient) authenticatedFormRequest(responseObject interface{}, method
 		payloadString = urlParams.Encode()
 	}
 
-	err = c.doSignedRequest(responseObject, method, urlPath, queryParams, contentType, payloadString)
+	err = c.doSignedRequest(ctx, endpoint, responseObject, method, urlPath, queryParams, contentType, payloadString)
 	return
 }
 
-func (c *HttpClient) authenticatedJsonRequest(responseObject interface{}, method string, urlPath string, urlParams *url.Values, requestObject interface{}) (err error) {
+func (c *HttpClient) authenticatedJsonRequest(ctx context.Context, endpoint string, responseObject interface{}, method string, urlPath string, urlParams *url.Values, requestObject interface{}) (err error) {
 	contentType := "application/json"
 	var payloadString string
 	var payloadBytes []byte
@@ -124,7 +166,7 @@ func (c *HttpClient) authenticatedJsonRequest(responseObject interface{}, method
 		}
 	}
 
-	err = c.doSignedRequest(responseObject, method, urlPath, urlParams, contentType, payloadString)
+	err = c.doSignedRequest(ctx, endpoint, responseObject, method, urlPath, urlParams, contentType, payloadString)
 	return
 }
 
@@ -132,8 +174,30 @@ type PaginationWrapper struct {
 	Data interface{} `json:"data"`
 }
 
-func (c *HttpClient) doSignedRequest(responseObject interface{}, method string, urlPath string, urlParams *url.Values, contentType string, payloadString string) (err error) {
-	url_ := urlMerge(c.domain, urlPath, urlParams)
+func (c *HttpClient) doSignedRequest(ctx context.Context, endpoint string, responseObject interface{}, method string, urlPath string, urlParams *url.Values, contentType string, payloadString string) (err error) {
+	request := RequestInfo{
+		Endpoint:    endpoint,
+		Method:      method,
+		Path:        urlPath,
+		Url:         urlMerge(c.domain, urlPath, urlParams),
+		Signed:      true,
+		ContentType: contentType,
+		Payload:     payloadString,
+	}
+
+	// every attempt is signed separately, so retries get a fresh nonce and timestamp
+	return c.withRetries(ctx, request, func() error {
+		return c.doSignedRequestOnce(ctx, responseObject, request)
+	})
+}
+
+func (c *HttpClient) doSignedRequestOnce(ctx context.Context, responseObject interface{}, request RequestInfo) (err error) {
+	// wait before signing, so a throttled request doesn't end up with a stale timestamp
+	if err = c.waitRateLimit(ctx, true); err != nil {
+		return
+	}
+
+	method, url_, contentType, payloadString := request.Method, request.Url, request.ContentType, request.Payload
 	authVersion := "v2"
 	xAuth := "BITSTAMP " + c.apiKey
 	apiSecret := []byte(c.apiSecret)
@@ -151,12 +215,11 @@ func (c *HttpClient) doSignedRequest(responseObject interface{}, method string,
 	signature := hex.EncodeToString(sig.Sum(nil))
 
 	// do the request
-	client := &http.Client{}
 	var req *http.Request
 	if payloadString == "" {
-		req, err = http.NewRequest(method, url_, nil)
+		req, err = http.NewRequestWithContext(ctx, method, url_, nil)
 	} else {
-		req, err = http.NewRequest(method, url_, bytes.NewBuffer([]byte(payloadString)))
+		req, err = http.NewRequestWithContext(ctx, method, url_, bytes.NewBuffer([]byte(payloadString)))
 	}
 	if err != nil {
 		return err
@@ -169,62 +232,52 @@ func (c *HttpClient) doSignedRequest(responseObject interface{}, method string,
 	if payloadString != "" {
 		req.Header.Add("Content-Type", contentType)
 	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	// handle response
-	if resp.StatusCode != 200 && resp.StatusCode != 201 && resp.StatusCode != 204 {
-		if resp.StatusCode == 503 {
-			err = errors.New("service unavailable")
-			return
+	call := &Call{Request: request, Header: req.Header}
+	return c.roundTrip(ctx, call, func(ctx context.Context) (err error) {
+		resp, err := c.httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return contextError(ctx, err)
 		}
-		var errorMsg map[string]interface{}
-		err = json.Unmarshal(respBody, &errorMsg)
+		defer resp.Body.Close()
+		call.StatusCode = resp.StatusCode
+
+		respBody, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return err
+			return contextError(ctx, err)
 		}
 
-		reasonVal, reasonPresent := errorMsg["reason"]
-		codeVal, codePresent := errorMsg["code"]
-		if reasonPresent && codePresent {
-			err = fmt.Errorf("%s %s (%d)", codeVal, reasonVal, resp.StatusCode)
-			return err
+		// handle response
+		if resp.StatusCode != 200 && resp.StatusCode != 201 && resp.StatusCode != 204 {
+			return newApiError(resp.StatusCode, resp.Status, url_, respBody)
 		} else {
-			err = fmt.Errorf("%s (%d)", string(respBody), resp.StatusCode)
-			return err
-		}
-	} else {
-		// verify server signature
-		checkMsg := nonce + timestamp_ + resp.Header.Get("Content-Type") + string(respBody)
-		sig := hmac.New(sha256.New, apiSecret)
-		sig.Write([]byte(checkMsg))
-		serverSig := hex.EncodeToString(sig.Sum(nil))
-		if serverSig != resp.Header.Get("X-Server-Auth-Signature") {
-			err = fmt.Errorf("server signature mismatch: us (%s) them (%s)", serverSig, resp.Header.Get("X-Server-Auth-Signature"))
-			return err
-		}
-		if len(respBody) > 0 {
-			err = json.Unmarshal(respBody, responseObject)
-			if err != nil {
-				var wrapped PaginationWrapper
-				wrapped = PaginationWrapper{Data: responseObject}
-				err = json.Unmarshal(respBody, &wrapped)
-				responseObject = wrapped.Data
+			// verify server signature
+			checkMsg := nonce + timestamp_ + resp.Header.Get("Content-Type") + string(respBody)
+			sig := hmac.New(sha256.New, apiSecret)
+			sig.Write([]byte(checkMsg))
+			serverSig := hex.EncodeToString(sig.Sum(nil))
+			if serverSig != resp.Header.Get("X-Server-Auth-Signature") {
+				err = fmt.Errorf("server signature mismatch: us (%s) them (%s)", serverSig, resp.Header.Get("X-Server-Auth-Signature"))
+				return err
+			}
+			if apiErr := responseError(resp.StatusCode, resp.Status, url_, respBody); apiErr != nil {
+				_ = json.Unmarshal(respBody, responseObject) // best effort, keeps response's status/reason populated
+				return apiErr
+			}
+			if len(respBody) > 0 {
+				err = json.Unmarshal(respBody, responseObject)
 				if err != nil {
-					return err
+					var wrapped PaginationWrapper
+					wrapped = PaginationWrapper{Data: responseObject}
+					err = json.Unmarshal(respBody, &wrapped)
+					responseObject = wrapped.Data
+					if err != nil {
+						return err
 
+					}
 				}
 			}
 		}
-	}
 
-	return nil
+		return nil
+	})
 }
@@ -1,8 +1,15 @@
 package http
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 
@@ -42,3 +49,73 @@ func TestApiClient_V2Ticker(t *testing.T) {
 	assert.IsType(t, decimal.Decimal{}, resp.Volume)
 	assert.True(t, resp.High.GreaterThanOrEqual(resp.Low))
 }
+
+func TestApiClient_ContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.V1TickerWithContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = c.V2AccountBalancesWithContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// newSignedTestServer starts a server that responds like the signed part of the API would, i.e. with
+// a valid X-Server-Auth-Signature for the given secret.
+func newSignedTestServer(t *testing.T, apiSecret string, handler func(r *http.Request) (int, string)) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statusCode, body := handler(r)
+		contentType := "application/json"
+		checkMsg := r.Header.Get("X-Auth-Nonce") + r.Header.Get("X-Auth-Timestamp") + contentType + body
+		sig := hmac.New(sha256.New, []byte(apiSecret))
+		sig.Write([]byte(checkMsg))
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("X-Server-Auth-Signature", hex.EncodeToString(sig.Sum(nil)))
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestApiClient_ErrorResponses(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		body       string
+		sentinel   error
+		message    string
+	}{
+		{"embedded status error", 200, `{"status": "error", "reason": {"__all__": ["You need 158338.86 USD to open that order. You have only 99991.52 USD available."]}}`, ErrInsufficientBalance, "You need 158338.86 USD"},
+		{"auth error", 403, `{"status": "error", "reason": "Invalid nonce", "code": "API0004"}`, ErrInvalidNonce, "API0004 Invalid nonce"},
+		{"order not found", 200, `{"status": "error", "reason": "Order not found."}`, ErrOrderNotFound, "Order not found."},
+		{"rate limited", 429, `{}`, ErrRateLimited, "429"},
+		{"maintenance", 503, `<html>down for maintenance</html>`, ErrMaintenance, "<html>"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+				return tc.statusCode, tc.body
+			})
+			c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+			_, err := c.V2OrderStatus(1, "", true)
+			var apiErr *ApiError
+			assert.ErrorAs(t, err, &apiErr)
+			assert.ErrorIs(t, err, tc.sentinel)
+			assert.Equal(t, tc.statusCode, apiErr.StatusCode)
+			assert.Equal(t, server.URL+"/v2/order_status/", apiErr.Url)
+			assert.Contains(t, err.Error(), tc.message)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+//
+// Liquidation addresses, i.e. bitcoin deposit addresses whose deposits are sold for fiat right away
+//
+
+type BtcAddressFormat string
+
+const (
+	P2SH   BtcAddressFormat = "P2SH" // the default
+	Bech32 BtcAddressFormat = "bech32"
+)
+
+type V2NewLiquidationAddressResponse struct {
+	Address string `json:"address"`
+}
+
+// POST https://www.bitstamp.net/api/v2/liquidation_address/new/
+//
+// Creates a new bitcoin deposit address whose deposits are sold for liquidationCurrency (e.g. "usd" or "eur").
+func (c *HttpClient) V2NewLiquidationAddress(liquidationCurrency string, addressFormat *BtcAddressFormat) (response V2NewLiquidationAddressResponse, err error) {
+	return c.V2NewLiquidationAddressWithContext(context.Background(), liquidationCurrency, addressFormat)
+}
+
+func (c *HttpClient) V2NewLiquidationAddressWithContext(ctx context.Context, liquidationCurrency string, addressFormat *BtcAddressFormat) (response V2NewLiquidationAddressResponse, err error) {
+	if liquidationCurrency == "" {
+		err = errors.New("liquidation currency is required")
+		return
+	}
+	params := map[string]string{"liquidation_currency": strings.ToLower(liquidationCurrency)}
+	if addressFormat != nil {
+		params["address_format"] = string(*addressFormat)
+	}
+
+	err = c.authenticatedFormRequest(ctx, "V2NewLiquidationAddress", &response, "POST", "/v2/liquidation_address/new/", nil, params)
+	if err != nil {
+		err = fmt.Errorf("error creating %s liquidation address: %w", liquidationCurrency, err)
+	}
+	return
+}
+
+type V2LiquidationTrade struct {
+	ExchangeRate decimal.Decimal `json:"exchange_rate"`
+	BtcAmount    decimal.Decimal `json:"btc_amount"`
+	Fees         decimal.Decimal `json:"fees"`
+}
+
+// V2LiquidationTransaction is the sell order placed for a deposit, executed in one or more trades.
+type V2LiquidationTransaction struct {
+	OrderId StringInt            `json:"order_id"`
+	Count   int                  `json:"count"` // number of trades
+	Trades  []V2LiquidationTrade `json:"trades"`
+}
+
+type V2LiquidationAddressInfoResponse struct {
+	Address      string                     `json:"address"`
+	CurrencyPair string                     `json:"currency_pair"` // e.g. "BTC/USD"
+	Transactions []V2LiquidationTransaction `json:"transactions"`
+}
+
+// POST https://www.bitstamp.net/api/v2/liquidation_address/info/
+//
+// Lists liquidation addresses with their transactions, only the given one if address is not nil.
+func (c *HttpClient) V2LiquidationAddressInfo(address *string) (response []V2LiquidationAddressInfoResponse, err error) {
+	return c.V2LiquidationAddressInfoWithContext(context.Background(), address)
+}
+
+func (c *HttpClient) V2LiquidationAddressInfoWithContext(ctx context.Context, address *string) (response []V2LiquidationAddressInfoResponse, err error) {
+	var params map[string]string
+	if address != nil {
+		params = map[string]string{"address": *address}
+	}
+
+	err = c.authenticatedFormRequest(ctx, "V2LiquidationAddressInfo", &response, "POST", "/v2/liquidation_address/info/", nil, params)
+	return
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV2LiquidationAddress(t *testing.T) {
+	var forms []url.Values
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		_ = r.ParseForm()
+		forms = append(forms, r.PostForm)
+		if r.URL.Path == "/v2/liquidation_address/new/" {
+			return 200, `{"address": "bc1qliquidation"}`
+		}
+		return 200, `[{"address": "bc1qliquidation", "currency_pair": "BTC/EUR", "transactions": [
+			{"order_id": "123", "count": 2, "trades": [
+				{"exchange_rate": "60000.00", "btc_amount": "0.05", "fees": "4.50"},
+				{"exchange_rate": "60010.00", "btc_amount": "0.05", "fees": "4.50"}
+			]}
+		]}]`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+	format := Bech32
+
+	created, err := c.V2NewLiquidationAddress("EUR", &format)
+	assert.NoError(t, err)
+	assert.Equal(t, "bc1qliquidation", created.Address)
+	assert.Equal(t, url.Values{"liquidation_currency": {"eur"}, "address_format": {"bech32"}}, forms[0])
+
+	infos, err := c.V2LiquidationAddressInfo(&created.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, "bc1qliquidation", forms[1].Get("address"))
+	if assert.Len(t, infos, 1) && assert.Len(t, infos[0].Transactions, 1) {
+		transaction := infos[0].Transactions[0]
+		assert.Equal(t, StringInt(123), transaction.OrderId)
+		assert.Len(t, transaction.Trades, 2)
+		assert.True(t, decimal.RequireFromString("60010").Equal(transaction.Trades[1].ExchangeRate))
+	}
+
+	_, err = c.V2LiquidationAddressInfo(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, forms[2])
+
+	_, err = c.V2NewLiquidationAddress("", nil)
+	assert.Error(t, err)
+	assert.Len(t, forms, 3)
+}
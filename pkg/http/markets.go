@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+//
+// Markets
+//
+
+type V2MarketsResponse struct {
+	Name                   string          `json:"name"`          // e.g. "BTC/USD-PERP", as taken by the derivatives endpoints
+	MarketSymbol           string          `json:"market_symbol"` // e.g. "btcusd-perp", as taken by the order endpoints
+	MarketType             MarketType      `json:"market_type"`
+	BaseCurrency           string          `json:"base_currency"`
+	CounterCurrency        string          `json:"counter_currency"`
+	BaseDecimals           int             `json:"base_decimals"`
+	CounterDecimals        int             `json:"counter_decimals"`
+	TickSize               decimal.Decimal `json:"tick_size"` // smallest price increment
+	LotSize                decimal.Decimal `json:"lot_size"`  // smallest amount increment
+	MinimumOrderValue      decimal.Decimal `json:"minimum_order_value"`
+	Trading                StringEnabled   `json:"trading"`
+	InstantAndMarketOrders StringEnabled   `json:"instant_and_market_orders"`
+	Description            string          `json:"description"`
+
+	// perpetual markets only
+	UnderlyingAsset string          `json:"underlying_asset"`
+	ContractSize    decimal.Decimal `json:"contract_size"`
+	MaxLeverage     decimal.Decimal `json:"max_leverage"`
+	FundingInterval StringInt       `json:"funding_interval"` // seconds between funding payments
+}
+
+// IsPerpetual tells perpetual futures markets apart from spot ones.
+func (m V2MarketsResponse) IsPerpetual() bool {
+	return m.MarketType == Perpetual || strings.HasSuffix(m.MarketSymbol, "-perp")
+}
+
+func (c *HttpClient) V2Markets() (response []V2MarketsResponse, err error) {
+	return c.V2MarketsWithContext(context.Background())
+}
+
+func (c *HttpClient) V2MarketsWithContext(ctx context.Context) (response []V2MarketsResponse, err error) {
+	err = c.getRequest(ctx, "V2Markets", &response, "/v2/markets/", nil)
+	return
+}
+
+// MarketDirectory indexes V2Markets by both url (market) symbol and name, so either can be looked up from the
+// other, e.g. to pass a perpetual traded as "btcusd-perp" to V2DerivativesClosePositions as "BTC/USD-PERP".
+type MarketDirectory struct {
+	bySymbol map[string]V2MarketsResponse
+	byName   map[string]V2MarketsResponse
+}
+
+func NewMarketDirectory(markets []V2MarketsResponse) MarketDirectory {
+	directory := MarketDirectory{
+		bySymbol: make(map[string]V2MarketsResponse, len(markets)),
+		byName:   make(map[string]V2MarketsResponse, len(markets)),
+	}
+	for _, market := range markets {
+		directory.bySymbol[strings.ToLower(market.MarketSymbol)] = market
+		directory.byName[strings.ToUpper(market.Name)] = market
+	}
+	return directory
+}
+
+// MarketDirectory loads V2Markets into a directory.
+func (c *HttpClient) MarketDirectory(ctx context.Context) (MarketDirectory, error) {
+	markets, err := c.V2MarketsWithContext(ctx)
+	if err != nil {
+		return MarketDirectory{}, fmt.Errorf("error loading markets: %w", err)
+	}
+	return NewMarketDirectory(markets), nil
+}
+
+// Market looks up a market by url symbol (e.g. "btcusd-perp") or name (e.g. "BTC/USD-PERP"), case-insensitive.
+func (md MarketDirectory) Market(symbolOrName string) (V2MarketsResponse, bool) {
+	if market, exists := md.bySymbol[strings.ToLower(symbolOrName)]; exists {
+		return market, true
+	}
+	market, exists := md.byName[strings.ToUpper(symbolOrName)]
+	return market, exists
+}
+
+// MarketName maps a url symbol to the market's name, e.g. "btcusd-perp" to "BTC/USD-PERP".
+func (md MarketDirectory) MarketName(urlSymbol string) (string, bool) {
+	market, exists := md.bySymbol[strings.ToLower(urlSymbol)]
+	return market.Name, exists
+}
+
+// UrlSymbol maps a market name to its url symbol, e.g. "BTC/USD-PERP" to "btcusd-perp".
+func (md MarketDirectory) UrlSymbol(marketName string) (string, bool) {
+	market, exists := md.byName[strings.ToUpper(marketName)]
+	return market.MarketSymbol, exists
+}
+
+// Markets returns all markets of the given type, all of them if marketType is empty, sorted by url symbol.
+// Perpetual and spot markets are told apart by IsPerpetual, i.e. also when the API leaves out the market type.
+func (md MarketDirectory) Markets(marketType MarketType) []V2MarketsResponse {
+	markets := make([]V2MarketsResponse, 0, len(md.bySymbol))
+	for _, market := range md.bySymbol {
+		var matches bool
+		switch marketType {
+		case "":
+			matches = true
+		case Perpetual:
+			matches = market.IsPerpetual()
+		case Spot:
+			matches = !market.IsPerpetual()
+		default:
+			matches = market.MarketType == marketType
+		}
+		if matches {
+			markets = append(markets, market)
+		}
+	}
+	sort.Slice(markets, func(i, j int) bool { return markets[i].MarketSymbol < markets[j].MarketSymbol })
+	return markets
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarketDirectory(t *testing.T) {
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		return 200, `[
+			{"name": "BTC/USD", "market_symbol": "btcusd", "market_type": "SPOT", "base_currency": "BTC", "counter_currency": "USD", "base_decimals": 8, "counter_decimals": 0, "tick_size": "1", "lot_size": "0.00000001", "minimum_order_value": "10", "trading": "Enabled", "instant_and_market_orders": "Enabled"},
+			{"name": "ETH/USD-PERP", "market_symbol": "ethusd-perp", "base_currency": "ETH", "counter_currency": "USD", "base_decimals": 4, "counter_decimals": 1, "tick_size": "0.1", "lot_size": "0.0001", "trading": "Enabled", "instant_and_market_orders": "Enabled", "underlying_asset": "ETH", "contract_size": "1", "max_leverage": "10", "funding_interval": 3600},
+			{"name": "BTC/USD-PERP", "market_symbol": "btcusd-perp", "market_type": "PERPETUAL", "base_currency": "BTC", "counter_currency": "USD", "base_decimals": 5, "counter_decimals": 0, "tick_size": "1", "lot_size": "0.00001", "trading": "Enabled", "instant_and_market_orders": "Disabled", "underlying_asset": "BTC", "contract_size": "1", "max_leverage": "20", "funding_interval": "3600"}
+		]`
+	})
+	c := NewHttpClient(UrlDomain(server.URL))
+
+	markets, err := c.MarketDirectory(context.Background())
+	assert.NoError(t, err)
+
+	name, exists := markets.MarketName("BTCUSD-PERP")
+	assert.True(t, exists)
+	assert.Equal(t, "BTC/USD-PERP", name)
+	symbol, exists := markets.UrlSymbol("eth/usd-perp")
+	assert.True(t, exists)
+	assert.Equal(t, "ethusd-perp", symbol)
+	_, exists = markets.UrlSymbol("DOGE/USD-PERP")
+	assert.False(t, exists)
+
+	perp, exists := markets.Market("BTC/USD-PERP")
+	assert.True(t, exists)
+	assert.True(t, perp.IsPerpetual())
+	assert.True(t, decimal.NewFromInt(20).Equal(perp.MaxLeverage))
+	assert.Equal(t, StringInt(3600), perp.FundingInterval)
+	assert.False(t, bool(perp.InstantAndMarketOrders))
+
+	spot, exists := markets.Market("btcusd")
+	assert.True(t, exists)
+	assert.False(t, spot.IsPerpetual())
+	assert.True(t, decimal.RequireFromString("0.00000001").Equal(spot.LotSize))
+
+	perpetuals := markets.Markets(Perpetual)
+	if assert.Len(t, perpetuals, 2) {
+		assert.Equal(t, "btcusd-perp", perpetuals[0].MarketSymbol)
+		assert.Equal(t, "ethusd-perp", perpetuals[1].MarketSymbol)
+	}
+	assert.Len(t, markets.Markets(Spot), 1)
+	assert.Len(t, markets.Markets(""), 3)
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Call is a single round trip to the API, as seen by a Middleware. Retried requests result in several calls.
+type Call struct {
+	Request    RequestInfo
+	Header     http.Header   // outgoing request headers (incl. X-Auth-* ones of signed requests), may be modified
+	StatusCode int           // response status code, 0 until (or unless) a response has been received
+	Latency    time.Duration // duration of the round trip, set once next returns
+}
+
+// RedactedHeader returns a copy of the request headers that is safe to log. The API secret itself never leaves
+// the client, but the request signature is replaced as well.
+func (c *Call) RedactedHeader() http.Header {
+	header := c.Header.Clone()
+	if header.Get("X-Auth-Signature") != "" {
+		header.Set("X-Auth-Signature", "REDACTED")
+	}
+	return header
+}
+
+type Next func(ctx context.Context) error
+
+// Middleware wraps the round trip of every (public and signed) API request. It must call next to perform the
+// request and gets back the decoded error (e.g. *ApiError) or nil. For example, a simple logger:
+//
+//	func logger(ctx context.Context, call *http.Call, next http.Next) error {
+//		err := next(ctx)
+//		log.Printf("%s %s %s: %d in %v (%v)", call.Request.Endpoint, call.Request.Method, call.Request.Url, call.StatusCode, call.Latency, err)
+//		return err
+//	}
+type Middleware func(ctx context.Context, call *Call, next Next) error
+
+// roundTrip runs do wrapped in the configured middlewares, the first one being the outermost.
+func (c *HttpClient) roundTrip(ctx context.Context, call *Call, do Next) error {
+	next := func(ctx context.Context) error {
+		start := time.Now()
+		err := do(ctx)
+		call.Latency = time.Since(start)
+		return err
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		middleware, inner := c.middlewares[i], next
+		next = func(ctx context.Context) error {
+			return middleware(ctx, call, inner)
+		}
+	}
+	return next(ctx)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewares(t *testing.T) {
+	var receivedHeader http.Header
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		receivedHeader = r.Header
+		if r.URL.Path == "/v2/order_status/" {
+			return 200, `{"status": "error", "reason": "Order not found"}`
+		}
+		return 200, `[]`
+	})
+
+	var trace []string
+	var calls []Call
+	tracer := func(name string) Middleware {
+		return func(ctx context.Context, call *Call, next Next) error {
+			trace = append(trace, name+" before")
+			err := next(ctx)
+			trace = append(trace, name+" after")
+			return err
+		}
+	}
+	recorder := func(ctx context.Context, call *Call, next Next) error {
+		call.Header.Set("X-Request-Source", "test")
+		err := next(ctx)
+		calls = append(calls, *call)
+		assert.Equal(t, "REDACTED", call.RedactedHeader().Get("X-Auth-Signature"))
+		assert.NotEqual(t, "REDACTED", call.Header.Get("X-Auth-Signature"))
+		if call.Request.Endpoint == "V2OrderStatus" {
+			assert.ErrorIs(t, err, ErrOrderNotFound)
+		}
+		return err
+	}
+
+	c := NewHttpClient(
+		UrlDomain(server.URL),
+		Credentials("key", "secret"),
+		Middlewares(tracer("first"), tracer("second")),
+		Middlewares(recorder),
+	)
+
+	_, err := c.V2OpenOrders("all")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, trace)
+	assert.Equal(t, "test", receivedHeader.Get("X-Request-Source"))
+
+	_, err = c.V2OrderStatus(1, "", false)
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+
+	assert.Len(t, calls, 2)
+	assert.Equal(t, "V2OpenOrders", calls[0].Request.Endpoint)
+	assert.Equal(t, "POST", calls[0].Request.Method)
+	assert.Equal(t, server.URL+"/v2/open_orders/all/", calls[0].Request.Url)
+	assert.Equal(t, 200, calls[0].StatusCode)
+	assert.Positive(t, calls[0].Latency)
+	assert.True(t, calls[0].Request.Signed)
+	assert.Equal(t, "V2OrderStatus", calls[1].Request.Endpoint)
+}
@@ -0,0 +1,445 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type OrderType string
+
+const (
+	LimitOrder        OrderType = "LIMIT"
+	MarketOrder       OrderType = "MARKET"
+	InstantOrder      OrderType = "INSTANT"
+	StopOrder         OrderType = "STOP"       // stop-market order
+	StopLimitOrder    OrderType = "STOP_LIMIT" // limit order placed once stop price is reached
+	TrailingStopOrder OrderType = "TRAILING_STOP"
+)
+
+// TriggerType is the price a stop or trailing stop order is triggered by.
+type TriggerType string
+
+const (
+	TriggerLastPrice  TriggerType = "last"  // last traded price, the default
+	TriggerMarkPrice  TriggerType = "mark"  // perpetual markets only
+	TriggerIndexPrice TriggerType = "index" // perpetual markets only
+)
+
+type OrderSide string
+
+const (
+	Buy  OrderSide = "buy"
+	Sell OrderSide = "sell"
+)
+
+// OrderRequest describes an order to be placed via SubmitOrder or PlaceOrderRequest, replacing the long lists of
+// positional arguments of V2BuyLimitOrder & co. Use one of the New*Order constructors and set optional fields as
+// needed, e.g.:
+//
+//	order := http.NewLimitOrder(http.Buy, "btcusd", price, amount)
+//	order.IocOrder = true
+//	order.ClientOrderId = "my-order-1"
+type OrderRequest struct {
+	Type         OrderType
+	Side         OrderSide
+	CurrencyPair string
+	// in base currency, except for instant buy orders where it is the amount of counter currency to spend
+	Amount decimal.Decimal
+
+	Price           decimal.Decimal // limit and stop-limit orders
+	LimitPrice      decimal.Decimal // limit orders, optional: price of the opposite order placed once this one executes
+	StopPrice       decimal.Decimal // stop and stop-limit orders
+	TrailingDelta   decimal.Decimal // trailing stop orders
+	ActivationPrice decimal.Decimal // trailing stop orders, optional
+	Trigger         TriggerType     // stop, stop-limit and trailing stop orders, optional
+
+	// limit order execution flags, at most one of them may be set
+	DailyOrder bool      // valid until midnight
+	IocOrder   bool      // immediate-or-cancel
+	FokOrder   bool      // fill-or-kill
+	MocOrder   bool      // maker-or-cancel
+	GtdOrder   bool      // good-till-date, requires ExpireTime
+	ExpireTime time.Time // expiry of GTD orders
+
+	ClientOrderId string
+	MarginMode    *MarginMode      // perpetual markets only
+	Leverage      *decimal.Decimal // perpetual markets only
+	ReduceOnly    bool             // perpetual markets only
+}
+
+func NewLimitOrder(side OrderSide, currencyPair string, price, amount decimal.Decimal) OrderRequest {
+	return OrderRequest{Type: LimitOrder, Side: side, CurrencyPair: currencyPair, Price: price, Amount: amount}
+}
+
+func NewMarketOrder(side OrderSide, currencyPair string, amount decimal.Decimal) OrderRequest {
+	return OrderRequest{Type: MarketOrder, Side: side, CurrencyPair: currencyPair, Amount: amount}
+}
+
+func NewInstantOrder(side OrderSide, currencyPair string, amount decimal.Decimal) OrderRequest {
+	return OrderRequest{Type: InstantOrder, Side: side, CurrencyPair: currencyPair, Amount: amount}
+}
+
+func NewStopOrder(side OrderSide, currencyPair string, stopPrice, amount decimal.Decimal) OrderRequest {
+	return OrderRequest{Type: StopOrder, Side: side, CurrencyPair: currencyPair, StopPrice: stopPrice, Amount: amount}
+}
+
+func NewStopLimitOrder(side OrderSide, currencyPair string, stopPrice, price, amount decimal.Decimal) OrderRequest {
+	return OrderRequest{Type: StopLimitOrder, Side: side, CurrencyPair: currencyPair, StopPrice: stopPrice, Price: price, Amount: amount}
+}
+
+func NewTrailingStopOrder(side OrderSide, currencyPair string, trailingDelta, amount decimal.Decimal) OrderRequest {
+	return OrderRequest{Type: TrailingStopOrder, Side: side, CurrencyPair: currencyPair, TrailingDelta: trailingDelta, Amount: amount}
+}
+
+// Validate checks the order for mistakes that can be caught without asking the exchange: unknown pair, missing or
+// superfluous fields (including perpetual-only ones on spot markets), too many decimal places, prices off the tick
+// size and conflicting execution flags. Pairs are looked up in the table
+// embedded in the library, SubmitOrder & co. use the client's PairRegistry instead.
+func (o OrderRequest) Validate() error {
+	pair, exists := embeddedPairs[o.CurrencyPair]
+	return o.validate(pair, exists)
+}
+
+func (o OrderRequest) validate(pair TradingPair, pairExists bool) error {
+	if o.Side != Buy && o.Side != Sell {
+		return fmt.Errorf("invalid order side: %q", o.Side)
+	}
+	if !pairExists {
+		return fmt.Errorf("unknown currency pair: %s", o.CurrencyPair)
+	}
+	if !pair.Trading {
+		return fmt.Errorf("trading is disabled for %s", o.CurrencyPair)
+	}
+	if !pair.InstantAndMarketOrders && (o.Type == MarketOrder || o.Type == InstantOrder) {
+		return fmt.Errorf("instant and market orders are disabled for %s", o.CurrencyPair)
+	}
+
+	if !o.Amount.IsPositive() {
+		return fmt.Errorf("amount must be positive: %s", o.Amount)
+	}
+	amountDecimals := pair.BaseDecimals
+	if o.Type == InstantOrder && o.Side == Buy {
+		amountDecimals = pair.InstantOrderCounterDecimals
+	}
+	if err := validateDecimals("amount", o.Amount, amountDecimals); err != nil {
+		return err
+	}
+
+	needsPrice := o.Type == LimitOrder || o.Type == StopLimitOrder
+	needsStopPrice := o.Type == StopOrder || o.Type == StopLimitOrder
+	isTrailing := o.Type == TrailingStopOrder
+	switch o.Type {
+	case LimitOrder, MarketOrder, InstantOrder, StopOrder, StopLimitOrder, TrailingStopOrder:
+	default:
+		return fmt.Errorf("invalid order type: %q", o.Type)
+	}
+
+	prices := []struct {
+		name     string
+		value    decimal.Decimal
+		required bool
+		allowed  bool
+	}{
+		{"price", o.Price, needsPrice, needsPrice},
+		{"limit price", o.LimitPrice, false, o.Type == LimitOrder},
+		{"stop price", o.StopPrice, needsStopPrice, needsStopPrice},
+		{"trailing delta", o.TrailingDelta, isTrailing, isTrailing},
+		{"activation price", o.ActivationPrice, false, isTrailing},
+	}
+	for _, p := range prices {
+		if p.value.IsZero() {
+			if p.required {
+				return fmt.Errorf("%s is required for %s orders", p.name, o.Type)
+			}
+			continue
+		}
+		if !p.allowed {
+			return fmt.Errorf("%s is not supported for %s orders", p.name, o.Type)
+		}
+		if p.value.IsNegative() {
+			return fmt.Errorf("%s must be positive: %s", p.name, p.value)
+		}
+		if err := validateDecimals(p.name, p.value, pair.CounterDecimals); err != nil {
+			return err
+		}
+		if err := validateStep(p.name, p.value, pair.PriceStep); err != nil {
+			return err
+		}
+	}
+
+	if err := validateLimitPrice(o.Side, o.Price, o.LimitPrice); err != nil {
+		return err
+	}
+	if err := o.validateTrigger(); err != nil {
+		return err
+	}
+	if err := o.validatePerpetualOnly(pair); err != nil {
+		return err
+	}
+
+	return o.validateFlags()
+}
+
+// validatePerpetualOnly rejects the fields only perpetual markets support on other (i.e. spot) markets.
+func (o OrderRequest) validatePerpetualOnly(pair TradingPair) error {
+	if pair.Perpetual {
+		return nil
+	}
+	var fields []string
+	if o.Trigger == TriggerMarkPrice || o.Trigger == TriggerIndexPrice {
+		fields = append(fields, string(o.Trigger)+" price trigger")
+	}
+	if o.MarginMode != nil {
+		fields = append(fields, "margin mode")
+	}
+	if o.Leverage != nil {
+		fields = append(fields, "leverage")
+	}
+	if o.ReduceOnly {
+		fields = append(fields, "reduce only")
+	}
+	if len(fields) > 0 {
+		return fmt.Errorf("%s not supported on %s, only on perpetual markets", strings.Join(fields, ", "), o.CurrencyPair)
+	}
+	return nil
+}
+
+func (o OrderRequest) validateTrigger() error {
+	switch o.Trigger {
+	case "":
+		return nil
+	case TriggerLastPrice, TriggerMarkPrice, TriggerIndexPrice:
+	default:
+		return fmt.Errorf("invalid trigger: %q", o.Trigger)
+	}
+	if o.Type != StopOrder && o.Type != StopLimitOrder && o.Type != TrailingStopOrder {
+		return fmt.Errorf("trigger is not supported for %s orders", o.Type)
+	}
+	return nil
+}
+
+func (o OrderRequest) validateFlags() error {
+	var flags []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"daily_order", o.DailyOrder},
+		{"ioc_order", o.IocOrder},
+		{"fok_order", o.FokOrder},
+		{"moc_order", o.MocOrder},
+		{"gtd_order", o.GtdOrder},
+	} {
+		if f.set {
+			flags = append(flags, f.name)
+		}
+	}
+
+	if len(flags) > 0 && o.Type != LimitOrder && o.Type != StopLimitOrder {
+		return fmt.Errorf("%s not supported for %s orders", strings.Join(flags, ", "), o.Type)
+	}
+	if len(flags) > 1 {
+		return fmt.Errorf("%s are mutually exclusive", strings.Join(flags, ", "))
+	}
+	if o.GtdOrder && o.ExpireTime.IsZero() {
+		return errors.New("gtd_order requires an expire time")
+	}
+	if !o.GtdOrder && !o.ExpireTime.IsZero() {
+		return errors.New("expire time is only supported for gtd_order")
+	}
+	if o.GtdOrder && !o.ExpireTime.After(time.Now()) {
+		return fmt.Errorf("expire time is in the past: %s", o.ExpireTime)
+	}
+	return nil
+}
+
+func validateDecimals(name string, value decimal.Decimal, decimals int32) error {
+	if !value.Equal(value.Truncate(decimals)) {
+		return fmt.Errorf("%s %s has more than %d decimal places", name, value, decimals)
+	}
+	return nil
+}
+
+// validateStep checks that value is a multiple of step, unless step is unknown (zero).
+func validateStep(name string, value decimal.Decimal, step decimal.Decimal) error {
+	if step.IsPositive() && !value.Mod(step).IsZero() {
+		return fmt.Errorf("%s %s is not a multiple of the tick size %s", name, value, step)
+	}
+	return nil
+}
+
+// ValidateMinimumOrder checks the order's value against the pair's minimum order, which is denominated in the
+// counter currency. Orders without a known value (e.g. market orders) always pass.
+func (o OrderRequest) ValidateMinimumOrder(pairInfo V2TradingPairsInfoResponse) error {
+	return o.validateMinimumOrder(pairInfo.MinimumOrder)
+}
+
+func (o OrderRequest) validateMinimumOrder(minimum CurrencyAmount) error {
+	var value decimal.Decimal
+	switch {
+	case o.Type == InstantOrder && o.Side == Buy:
+		value = o.Amount
+	case !o.Price.IsZero():
+		value = o.Amount.Mul(o.Price)
+	case !o.StopPrice.IsZero():
+		value = o.Amount.Mul(o.StopPrice)
+	default:
+		return nil
+	}
+
+	if value.LessThan(minimum.Amount) {
+		return fmt.Errorf("order value %s is below minimum order %s", value, minimum)
+	}
+	return nil
+}
+
+func (o OrderRequest) urlPath() string {
+	switch o.Type {
+	case MarketOrder, StopOrder, TrailingStopOrder:
+		return fmt.Sprintf("/v2/%s/market/%s/", o.Side, o.CurrencyPair)
+	case InstantOrder:
+		return fmt.Sprintf("/v2/%s/instant/%s/", o.Side, o.CurrencyPair)
+	default:
+		return fmt.Sprintf("/v2/%s/%s/", o.Side, o.CurrencyPair)
+	}
+}
+
+func (o OrderRequest) params() map[string]string {
+	params := map[string]string{
+		"amount": o.Amount.String(),
+	}
+
+	optionalPrices := map[string]decimal.Decimal{
+		"price":            o.Price,
+		"limit_price":      o.LimitPrice,
+		"stop_price":       o.StopPrice,
+		"trailing_delta":   o.TrailingDelta,
+		"activation_price": o.ActivationPrice,
+	}
+	for name, value := range optionalPrices {
+		if !value.IsZero() {
+			params[name] = value.String()
+		}
+	}
+
+	flags := map[string]bool{
+		"daily_order": o.DailyOrder,
+		"ioc_order":   o.IocOrder,
+		"fok_order":   o.FokOrder,
+		"moc_order":   o.MocOrder,
+		"gtd_order":   o.GtdOrder,
+		"reduce_only": o.ReduceOnly,
+	}
+	for name, set := range flags {
+		if set {
+			params[name] = "True"
+		}
+	}
+	if o.GtdOrder {
+		params["expire_time"] = fmt.Sprintf("%d", o.ExpireTime.Unix())
+	}
+
+	if o.Trigger != "" {
+		params["trigger"] = string(o.Trigger)
+	}
+	if o.ClientOrderId != "" {
+		params["client_order_id"] = o.ClientOrderId
+	}
+	if o.MarginMode != nil {
+		params["margin_mode"] = string(*o.MarginMode)
+	}
+	if o.Leverage != nil {
+		params["leverage"] = o.Leverage.String()
+	}
+	return params
+}
+
+// V2OrderResponse is the common shape of limit, market, instant and stop order responses.
+type V2OrderResponse struct {
+	Id              string           `json:"id"`
+	Datetime        string           `json:"datetime"`
+	Type            string           `json:"type"`
+	Subtype         string           `json:"subtype"`
+	Market          string           `json:"market"`
+	Price           decimal.Decimal  `json:"price"`
+	Amount          decimal.Decimal  `json:"amount"`
+	LimitPrice      decimal.Decimal  `json:"limit_price"`
+	ClientOrderId   string           `json:"client_order_id"`
+	MarginMode      *MarginMode      `json:"margin_mode"`
+	Leverage        *decimal.Decimal `json:"leverage"`
+	StopPrice       decimal.Decimal  `json:"stop_price"`
+	Trigger         string           `json:"trigger"`
+	ActivationPrice decimal.Decimal  `json:"activation_price"`
+	TrailingDelta   decimal.Decimal  `json:"trailing_delta"`
+	Status          string           `json:"status"`
+	Reason          interface{}      `json:"reason"`
+}
+
+// SubmitOrder validates and places the order. With AutoRoundingEnabled, amount and prices are rounded to the
+// pair's decimals first.
+func (c *HttpClient) SubmitOrder(ctx context.Context, order OrderRequest) (response V2OrderResponse, err error) {
+	err = c.submitOrder(ctx, "SubmitOrder", order, &response)
+	return
+}
+
+func (c *HttpClient) submitOrder(ctx context.Context, endpoint string, order OrderRequest, response interface{}) error {
+	order = c.roundOrder(ctx, order)
+	if err := c.validateOrder(ctx, order); err != nil {
+		return err
+	}
+
+	err := c.authenticatedFormRequest(ctx, endpoint, response, "POST", order.urlPath(), nil, order.params())
+	if err != nil {
+		return fmt.Errorf("error placing %s %s (%s): %w", strings.ToLower(string(order.Type)), order.Side, order.Amount, err)
+	}
+	return nil
+}
+
+// PlaceOrderRequest validates the order and places it via PlaceOrder, i.e. makes sure it ends up on the exchange
+// at most once. Validation errors result in OrderRejected without anything being sent.
+func (c *HttpClient) PlaceOrderRequest(ctx context.Context, order OrderRequest) OrderPlacement {
+	order = c.roundOrder(ctx, order)
+	if err := c.validateOrder(ctx, order); err != nil {
+		return OrderPlacement{Status: OrderRejected, ClientOrderId: order.ClientOrderId, Err: err}
+	}
+
+	return c.PlaceOrder(ctx, order.CurrencyPair, order.ClientOrderId, func(ctx context.Context, clOrdId string) (string, error) {
+		order.ClientOrderId = clOrdId
+		resp, err := c.SubmitOrder(ctx, order)
+		return resp.Id, err
+	})
+}
+
+func (c *HttpClient) roundOrder(ctx context.Context, order OrderRequest) OrderRequest {
+	if !c.autoRounding {
+		return order
+	}
+	pair, exists := c.tradingPair(ctx, order.CurrencyPair)
+	if !exists {
+		return order
+	}
+	return c.roundingPolicy.round(order, pair)
+}
+
+func (c *HttpClient) validateOrder(ctx context.Context, order OrderRequest) error {
+	pair, exists := c.tradingPair(ctx, order.CurrencyPair)
+	if err := order.validate(pair, exists); err != nil {
+		return err
+	}
+	// minimum order is unknown while falling back to the embedded table, the exchange enforces it anyway
+	if !pair.MinimumOrder.Amount.IsZero() {
+		return order.validateMinimumOrder(pair.MinimumOrder)
+	}
+	return nil
+}
+
+// tradingPair looks up the pair in the client's registry, loading it first unless that has been tried before.
+func (c *HttpClient) tradingPair(ctx context.Context, currencyPair string) (TradingPair, bool) {
+	c.pairRegistry.loadOnce(ctx)
+	return c.pairRegistry.Pair(ctx, currencyPair)
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderRequest_Validate(t *testing.T) {
+	d := decimal.RequireFromString
+	gtd := NewLimitOrder(Buy, "btcusd", d("50000"), d("0.1"))
+	gtd.GtdOrder = true
+	gtd.ExpireTime = time.Now().Add(time.Hour)
+	dailyIoc := NewLimitOrder(Buy, "btcusd", d("50000"), d("0.1"))
+	dailyIoc.DailyOrder, dailyIoc.IocOrder = true, true
+	gtdNoExpiry := NewLimitOrder(Buy, "btcusd", d("50000"), d("0.1"))
+	gtdNoExpiry.GtdOrder = true
+	marketIoc := NewMarketOrder(Sell, "btcusd", d("0.1"))
+	marketIoc.IocOrder = true
+	takeProfit := NewLimitOrder(Buy, "btcusd", d("50000"), d("0.1"))
+	takeProfit.LimitPrice = d("55000")
+	badTakeProfit := NewLimitOrder(Sell, "btcusd", d("50000"), d("0.1"))
+	badTakeProfit.LimitPrice = d("55000")
+	markStop := NewStopOrder(Sell, "btcusd-perp", d("49000"), d("0.1"))
+	markStop.Trigger = TriggerMarkPrice
+	badTrigger := NewStopOrder(Sell, "btcusd", d("49000"), d("0.1"))
+	badTrigger.Trigger = "mid"
+	limitTrigger := NewLimitOrder(Buy, "btcusd", d("50000"), d("0.1"))
+	limitTrigger.Trigger = TriggerLastPrice
+	spotMarkStop := NewStopOrder(Sell, "btcusd", d("49000"), d("0.1"))
+	spotMarkStop.Trigger = TriggerMarkPrice
+	spotIndexStop := NewStopOrder(Sell, "btcusd", d("49000"), d("0.1"))
+	spotIndexStop.Trigger = TriggerIndexPrice
+	cross := Cross
+	leverage := d("5")
+	perpMargin := NewLimitOrder(Buy, "btcusd-perp", d("50000"), d("0.1"))
+	perpMargin.MarginMode, perpMargin.Leverage, perpMargin.ReduceOnly = &cross, &leverage, true
+	spotMargin := NewLimitOrder(Buy, "btcusd", d("50000"), d("0.1"))
+	spotMargin.MarginMode, spotMargin.Leverage = &cross, &leverage
+	spotReduceOnly := NewMarketOrder(Sell, "btcusd", d("0.1"))
+	spotReduceOnly.ReduceOnly = true
+
+	cases := []struct {
+		name  string
+		order OrderRequest
+		err   string
+	}{
+		{"limit", NewLimitOrder(Buy, "btcusd", d("50000"), d("0.1")), ""},
+		{"gtd", gtd, ""},
+		{"market", NewMarketOrder(Sell, "btcusd", d("0.1")), ""},
+		{"instant buy in counter currency", NewInstantOrder(Buy, "xrpusd", d("10.12345")), ""},
+		{"instant buy in cents", NewInstantOrder(Buy, "btcusd", d("10.50")), ""},
+		{"instant buy decimals", NewInstantOrder(Buy, "btcusd", d("10.505")), "more than 2 decimal places"},
+		{"stop limit", NewStopLimitOrder(Sell, "btcusd", d("49000"), d("48900"), d("0.1")), ""},
+		{"trailing stop", NewTrailingStopOrder(Sell, "btcusd", d("100"), d("0.1")), ""},
+		{"limit price", takeProfit, ""},
+		{"limit price on wrong side", badTakeProfit, "limit price 55000 of a sell order must be below price 50000"},
+		{"unknown pair", NewMarketOrder(Buy, "abcxyz", d("1")), "unknown currency pair"},
+		{"invalid side", NewMarketOrder("short", "btcusd", d("1")), "invalid order side"},
+		{"zero amount", NewMarketOrder(Buy, "btcusd", decimal.Zero), "amount must be positive"},
+		{"amount decimals", NewMarketOrder(Buy, "btcusd", d("0.123456789")), "more than 8 decimal places"},
+		{"price decimals", NewLimitOrder(Buy, "btcusd", d("50000.5"), d("0.1")), "more than 0 decimal places"},
+		{"missing price", NewLimitOrder(Buy, "btcusd", decimal.Zero, d("0.1")), "price is required"},
+		{"missing stop price", NewStopOrder(Buy, "btcusd", decimal.Zero, d("0.1")), "stop price is required"},
+		{"superfluous price", OrderRequest{Type: MarketOrder, Side: Buy, CurrencyPair: "btcusd", Amount: d("1"), Price: d("1")}, "price is not supported"},
+		{"stop on mark price", markStop, ""},
+		{"invalid trigger", badTrigger, `invalid trigger: "mid"`},
+		{"mark price trigger on spot", spotMarkStop, "mark price trigger not supported on btcusd, only on perpetual markets"},
+		{"index price trigger on spot", spotIndexStop, "index price trigger not supported on btcusd"},
+		{"margin on perpetual", perpMargin, ""},
+		{"margin on spot", spotMargin, "margin mode, leverage not supported on btcusd"},
+		{"reduce only on spot", spotReduceOnly, "reduce only not supported on btcusd"},
+		{"trigger on limit order", limitTrigger, "trigger is not supported for LIMIT orders"},
+		{"exclusive flags", dailyIoc, "daily_order, ioc_order are mutually exclusive"},
+		{"gtd without expiry", gtdNoExpiry, "requires an expire time"},
+		{"flags on market order", marketIoc, "ioc_order not supported for MARKET orders"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.order.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestOrderRequest_ValidateTickSize(t *testing.T) {
+	d := decimal.RequireFromString
+	pair := TradingPair{UrlSymbol: "btcusd", BaseDecimals: 8, CounterDecimals: 0, PriceStep: d("5"), Trading: true}
+
+	assert.NoError(t, NewLimitOrder(Buy, "btcusd", d("50005"), d("0.1")).validate(pair, true))
+	assert.ErrorContains(t, NewLimitOrder(Buy, "btcusd", d("50003"), d("0.1")).validate(pair, true), "price 50003 is not a multiple of the tick size 5")
+	assert.ErrorContains(t, NewStopOrder(Sell, "btcusd", d("49001"), d("0.1")).validate(pair, true), "stop price 49001 is not a multiple")
+	assert.ErrorContains(t, NewTrailingStopOrder(Sell, "btcusd", d("12"), d("0.1")).validate(pair, true), "trailing delta 12 is not a multiple")
+}
+
+func TestOrderRequest_ValidateMinimumOrder(t *testing.T) {
+	d := decimal.RequireFromString
+	info := V2TradingPairsInfoResponse{MinimumOrder: CurrencyAmount{d("10.00"), "USD"}}
+
+	assert.NoError(t, NewLimitOrder(Buy, "btcusd", d("50000"), d("0.001")).ValidateMinimumOrder(info))
+	assert.ErrorContains(t, NewLimitOrder(Buy, "btcusd", d("50000"), d("0.0001")).ValidateMinimumOrder(info), "below minimum order")
+	assert.ErrorContains(t, NewInstantOrder(Buy, "btcusd", d("5")).ValidateMinimumOrder(info), "below minimum order")
+	assert.NoError(t, NewMarketOrder(Sell, "btcusd", d("0.00001")).ValidateMinimumOrder(info))
+	assert.NoError(t, NewLimitOrder(Buy, "btcusd", d("1"), d("0.0001")).ValidateMinimumOrder(V2TradingPairsInfoResponse{}), "unknown minimum")
+}
+
+func TestSubmitOrder(t *testing.T) {
+	var form map[string][]string
+	var path string
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		if r.URL.Path == "/v2/trading-pairs-info/" {
+			return 200, `[{"url_symbol": "btcusd", "minimum_order": "10.00 USD", "base_decimals": 8, "counter_decimals": 0, "trading": "Enabled", "instant_and_market_orders": "Enabled"}]`
+		}
+		_ = r.ParseForm()
+		form, path = r.PostForm, r.URL.Path
+		return 200, `{"id": "1", "type": "0", "price": "50000", "amount": "0.1"}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+	order := NewLimitOrder(Buy, "btcusd", decimal.NewFromInt(50000), decimal.RequireFromString("0.1"))
+	order.GtdOrder = true
+	order.ExpireTime = time.Unix(4102444800, 0)
+	order.ClientOrderId = "my-order"
+	resp, err := c.SubmitOrder(context.Background(), order)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", resp.Id)
+	assert.Equal(t, "/v2/buy/btcusd/", path)
+	assert.Equal(t, map[string][]string{
+		"amount":          {"0.1"},
+		"price":           {"50000"},
+		"gtd_order":       {"True"},
+		"expire_time":     {"4102444800"},
+		"client_order_id": {"my-order"},
+	}, form)
+
+	path = ""
+	_, err = c.SubmitOrder(context.Background(), NewLimitOrder(Sell, "btcusd", decimal.NewFromInt(50000), decimal.RequireFromString("0.0001")))
+	assert.ErrorContains(t, err, "below minimum order")
+	assert.Empty(t, path, "invalid orders must not be sent")
+
+	placement := c.PlaceOrderRequest(context.Background(), NewMarketOrder(Sell, "btcusd", decimal.RequireFromString("0.1")))
+	assert.Equal(t, OrderPlaced, placement.Status)
+	assert.Equal(t, "/v2/sell/market/btcusd/", path)
+	assert.Equal(t, placement.ClientOrderId, form["client_order_id"][0])
+}
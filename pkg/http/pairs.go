@@ -0,0 +1,205 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// how often a lookup of an unknown pair may trigger a refresh, so new listings are picked up without
+// hammering the API with typos
+const pairRegistryMissRefreshInterval = time.Minute
+
+// TradingPair is what PairRegistry knows about a currency pair.
+type TradingPair struct {
+	UrlSymbol       string // e.g. "btcusd"
+	Name            string // e.g. "BTC/USD"
+	BaseDecimals    int32
+	CounterDecimals int32
+	// decimals of instant buy amounts, which are in counter currency, e.g. 2 for btcusd while prices have 0
+	InstantOrderCounterDecimals int32
+	// minimum order value, zero if unknown (i.e. while falling back to the embedded table)
+	MinimumOrder           CurrencyAmount
+	PriceStep              decimal.Decimal // smallest price increment
+	Trading                bool            // whether trading is enabled
+	InstantAndMarketOrders bool            // whether instant and market orders are enabled
+	Perpetual              bool            // perpetual futures market
+}
+
+func tradingPairFromInfo(info V2TradingPairsInfoResponse) TradingPair {
+	instantDecimals := int32(info.InstantOrderCounterDecimals)
+	if instantDecimals == 0 {
+		instantDecimals = defaultInstantOrderCounterDecimals(int32(info.CounterDecimals))
+	}
+	return TradingPair{
+		UrlSymbol:                   info.UrlSymbol,
+		Name:                        info.Name,
+		BaseDecimals:                int32(info.BaseDecimals),
+		CounterDecimals:             int32(info.CounterDecimals),
+		InstantOrderCounterDecimals: instantDecimals,
+		MinimumOrder:                info.MinimumOrder,
+		PriceStep:                   info.PriceStep(),
+		Trading:                     bool(info.Trading),
+		InstantAndMarketOrders:      bool(info.InstantAndMarketOrders),
+		Perpetual:                   info.IsPerpetual(),
+	}
+}
+
+// embeddedPairs is the fallback used until (or unless) a registry is loaded from the exchange. Never modified.
+var embeddedPairs = newEmbeddedPairs()
+
+func newEmbeddedPairs() map[string]TradingPair {
+	pairs := make(map[string]TradingPair, len(roundings))
+	for symbol, r := range roundings {
+		pairs[symbol] = TradingPair{
+			UrlSymbol:                   symbol,
+			BaseDecimals:                r.Base,
+			CounterDecimals:             r.Counter,
+			InstantOrderCounterDecimals: defaultInstantOrderCounterDecimals(r.Counter),
+			PriceStep:                   decimal.New(1, -r.Counter),
+			Trading:                     true,
+			InstantAndMarketOrders:      true,
+			Perpetual:                   strings.HasSuffix(symbol, "-perp"),
+		}
+	}
+	return pairs
+}
+
+// defaultInstantOrderCounterDecimals is used where trading pairs info doesn't tell the decimals of instant buy
+// amounts. Counter amounts have at least cents, rounding to more decimals than the exchange allows merely gets the
+// order rejected, to fewer would silently change it.
+func defaultInstantOrderCounterDecimals(counterDecimals int32) int32 {
+	if counterDecimals < 2 {
+		return 2
+	}
+	return counterDecimals
+}
+
+// PairRegistry keeps track of the tradable pairs and their decimals, minimum orders and trading status. It is
+// loaded from V2TradingPairsInfo and falls back to a table embedded in the library while the exchange can't be
+// reached. Every HttpClient has one (see HttpClient.Pairs), which is loaded the first time an order is validated
+// and whenever an unknown pair is looked up. Use Run to keep it up to date periodically, and the TradingPairs
+// option to share one between clients.
+type PairRegistry struct {
+	load func(ctx context.Context) ([]V2TradingPairsInfoResponse, error)
+
+	refreshLock sync.Mutex // serializes refreshes
+	lastAttempt time.Time
+
+	mu          sync.RWMutex
+	pairs       map[string]TradingPair
+	lastRefresh time.Time // of the last successful refresh, zero while using the embedded table
+}
+
+// NewPairRegistry returns a registry loading trading pairs info through the given client. It starts out with
+// the embedded table, call Refresh or Run to load it.
+func NewPairRegistry(client *HttpClient) *PairRegistry {
+	return newPairRegistry(client.V2TradingPairsInfoWithContext)
+}
+
+func newPairRegistry(load func(ctx context.Context) ([]V2TradingPairsInfoResponse, error)) *PairRegistry {
+	return &PairRegistry{
+		load:  load,
+		pairs: embeddedPairs,
+	}
+}
+
+// Refresh (re)loads the registry from the exchange. On error, the registry keeps its current pairs.
+func (r *PairRegistry) Refresh(ctx context.Context) error {
+	r.refreshLock.Lock()
+	defer r.refreshLock.Unlock()
+	return r.refresh(ctx)
+}
+
+func (r *PairRegistry) refresh(ctx context.Context) error {
+	r.lastAttempt = time.Now()
+	infos, err := r.load(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading trading pairs: %w", err)
+	}
+	if len(infos) == 0 {
+		return fmt.Errorf("error loading trading pairs: no pairs returned")
+	}
+
+	pairs := make(map[string]TradingPair, len(infos))
+	for _, info := range infos {
+		pairs[info.UrlSymbol] = tradingPairFromInfo(info)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pairs = pairs
+	r.lastRefresh = r.lastAttempt
+	return nil
+}
+
+// Run refreshes the registry right away and then every interval, until ctx is done. Failed refreshes are retried
+// on the next tick.
+func (r *PairRegistry) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_ = r.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// LastRefresh returns when the registry was last loaded from the exchange, zero if it is using the embedded table.
+func (r *PairRegistry) LastRefresh() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastRefresh
+}
+
+// Pairs returns all known pairs, sorted by url symbol.
+func (r *PairRegistry) Pairs() []TradingPair {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pairs := make([]TradingPair, 0, len(r.pairs))
+	for _, pair := range r.pairs {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].UrlSymbol < pairs[j].UrlSymbol })
+	return pairs
+}
+
+// Pair looks up a pair by url symbol (e.g. "btcusd"). Unknown pairs trigger a refresh (at most once a minute),
+// so pairs listed after the registry was loaded are found as well.
+func (r *PairRegistry) Pair(ctx context.Context, urlSymbol string) (TradingPair, bool) {
+	if pair, exists := r.cached(urlSymbol); exists {
+		return pair, true
+	}
+
+	r.refreshLock.Lock()
+	if time.Since(r.lastAttempt) >= pairRegistryMissRefreshInterval {
+		_ = r.refresh(ctx)
+	}
+	r.refreshLock.Unlock()
+	return r.cached(urlSymbol)
+}
+
+// cached looks up a pair without ever calling the API.
+func (r *PairRegistry) cached(urlSymbol string) (TradingPair, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pair, exists := r.pairs[urlSymbol]
+	return pair, exists
+}
+
+// loadOnce loads the registry unless it has been (attempted to be) loaded before.
+func (r *PairRegistry) loadOnce(ctx context.Context) {
+	r.refreshLock.Lock()
+	defer r.refreshLock.Unlock()
+	if r.lastAttempt.IsZero() {
+		_ = r.refresh(ctx)
+	}
+}
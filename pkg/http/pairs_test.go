@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPairRegistry(t *testing.T) {
+	infos := []V2TradingPairsInfoResponse{
+		{UrlSymbol: "btcusd", Name: "BTC/USD", BaseDecimals: 8, CounterDecimals: 0, MinimumOrder: CurrencyAmount{decimal.NewFromInt(10), "USD"}, Trading: true, InstantAndMarketOrders: true},
+	}
+	loads := 0
+	var loadErr error
+	registry := newPairRegistry(func(ctx context.Context) ([]V2TradingPairsInfoResponse, error) {
+		loads++
+		return infos, loadErr
+	})
+	ctx := context.Background()
+
+	// embedded table until loaded
+	pair, exists := registry.Pair(ctx, "ethusd")
+	assert.True(t, exists)
+	assert.Equal(t, 0, loads)
+	assert.True(t, pair.MinimumOrder.Amount.IsZero())
+	assert.True(t, registry.LastRefresh().IsZero())
+
+	// unknown pairs trigger a refresh
+	_, exists = registry.Pair(ctx, "newusd")
+	assert.False(t, exists)
+	assert.Equal(t, 1, loads)
+	pair, exists = registry.Pair(ctx, "btcusd")
+	assert.True(t, exists)
+	assert.Equal(t, "10 USD", pair.MinimumOrder.String())
+	assert.True(t, decimal.NewFromInt(1).Equal(pair.PriceStep))
+	assert.True(t, pair.Trading)
+	_, exists = registry.Pair(ctx, "ethusd")
+	assert.False(t, exists, "the exchange is the source of truth once loaded")
+
+	// ... but at most once a minute
+	infos = append(infos, V2TradingPairsInfoResponse{UrlSymbol: "newusd"})
+	_, exists = registry.Pair(ctx, "newusd")
+	assert.False(t, exists)
+	assert.Equal(t, 1, loads)
+
+	assert.NoError(t, registry.Refresh(ctx))
+	pair, exists = registry.Pair(ctx, "newusd")
+	assert.True(t, exists)
+	assert.False(t, pair.Trading)
+	assert.Len(t, registry.Pairs(), 2)
+
+	// failed refreshes keep the current pairs
+	loadErr = errors.New("offline")
+	assert.Error(t, registry.Refresh(ctx))
+	assert.Len(t, registry.Pairs(), 2)
+}
+
+func TestPairRegistry_Client(t *testing.T) {
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		switch r.URL.Path {
+		case "/v2/trading-pairs-info/":
+			return 200, `[
+				{"url_symbol": "newusd", "base_decimals": 2, "counter_decimals": 4, "minimum_order": "10 USD", "trading": "Enabled", "instant_and_market_orders": "Disabled"},
+				{"url_symbol": "oldusd", "base_decimals": 2, "counter_decimals": 4, "minimum_order": "10 USD", "trading": "Disabled", "instant_and_market_orders": "Disabled"}
+			]`
+		case "/v2/ticker/newusd/":
+			return 200, `{"last": "1.5", "timestamp": "1700000000"}`
+		}
+		return 200, `{"id": "1"}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+	ctx := context.Background()
+	d := decimal.RequireFromString
+
+	// pairs listed after the library was released are accepted
+	_, err := c.V2TickerWithContext(ctx, "newusd")
+	assert.NoError(t, err)
+
+	_, err = c.SubmitOrder(ctx, NewLimitOrder(Buy, "newusd", d("1.2345"), d("10.25")))
+	assert.NoError(t, err)
+	_, err = c.SubmitOrder(ctx, NewLimitOrder(Buy, "newusd", d("1.23456"), d("10.25")))
+	assert.ErrorContains(t, err, "more than 4 decimal places")
+	_, err = c.SubmitOrder(ctx, NewMarketOrder(Buy, "newusd", d("10")))
+	assert.ErrorContains(t, err, "instant and market orders are disabled for newusd")
+	_, err = c.SubmitOrder(ctx, NewLimitOrder(Buy, "oldusd", d("1"), d("10")))
+	assert.ErrorContains(t, err, "trading is disabled for oldusd")
+
+	// shared between clients
+	other := NewHttpClient(UrlDomain(server.URL), TradingPairs(c.Pairs()))
+	assert.Same(t, c.Pairs(), other.Pairs())
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// maximum number of times PlaceOrder submits an order
+const placeOrderAttempts = 3
+
+type OrderPlacementStatus string
+
+const (
+	OrderPlaced   OrderPlacementStatus = "PLACED"   // the exchange accepted the order
+	OrderRejected OrderPlacementStatus = "REJECTED" // the exchange definitely did not accept the order
+	OrderUnknown  OrderPlacementStatus = "UNKNOWN"  // could not be determined, reconcile later using ClientOrderId
+)
+
+type OrderPlacement struct {
+	Status        OrderPlacementStatus
+	OrderId       string
+	ClientOrderId string
+	Attempts      int   // number of times the order was submitted
+	Err           error // the rejection reason or, for OrderUnknown, the last error encountered
+}
+
+// PlaceOrderFunc submits an order with the given client order id and returns the order id assigned by the exchange.
+type PlaceOrderFunc func(ctx context.Context, clOrdId string) (orderId string, err error)
+
+// PlaceOrder submits an order and makes sure it ends up on the exchange at most once. A client order id is
+// generated unless clOrdId is given. When submitting fails ambiguously (network errors, timeouts, 5xx, ...), the
+// order is looked up by its client order id (V2OrderStatus, then V2OpenOrders) and only resubmitted if the exchange
+// does not know about it. The outcome is OrderRejected only if the exchange is known not to have the order, i.e.
+// the exchange answered the first submission with a refusal (with Retries enabled, the order is looked up first, as
+// the refused request might have been a resubmission); once an order has been resubmitted, failures end in
+// OrderUnknown unless the order is found.
+// For example:
+//
+//	placement := c.PlaceOrder(ctx, "btcusd", "", func(ctx context.Context, clOrdId string) (string, error) {
+//		resp, err := c.V2BuyLimitOrderWithContext(ctx, "btcusd", price, amount, decimal.Zero, false, false, clOrdId, nil, nil, false)
+//		return resp.Id, err
+//	})
+//
+// If ctx is done before the outcome is known, the result is OrderUnknown.
+func (c *HttpClient) PlaceOrder(ctx context.Context, currencyPair string, clOrdId string, place PlaceOrderFunc) (result OrderPlacement) {
+	if clOrdId == "" {
+		clOrdId = uuid.NewString()
+	}
+	result.ClientOrderId = clOrdId
+
+	for result.Attempts < placeOrderAttempts {
+		result.Attempts++
+		orderId, err := place(ctx, clOrdId)
+		if err == nil {
+			result.Status = OrderPlaced
+			result.OrderId = orderId
+			result.Err = nil
+			return
+		}
+		result.Err = err
+
+		// only an answer of the exchange refusing the order is definitive, anything else (timeouts, unreadable or
+		// unverifiable responses, ...) might have been accepted
+		var apiErr *ApiError
+		refused := errors.As(err, &apiErr) && !IsTransientError(err)
+		// with retries enabled, the refusal might be the answer to the retry layer resubmitting the order
+		if refused && result.Attempts == 1 && c.retryPolicy == nil {
+			// refused before anything could have landed
+			result.Status = OrderRejected
+			return
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		// ambiguous outcome, ask the exchange. that includes a resubmission being refused, as the exchange might
+		// refuse it because an earlier submission landed late (e.g. reusing the client order id).
+		orderId, found, findErr := c.findOrderByClientOrderId(ctx, currencyPair, clOrdId)
+		if findErr != nil {
+			result.Err = errors.Join(err, findErr)
+			break
+		}
+		if found {
+			result.Status = OrderPlaced
+			result.OrderId = orderId
+			result.Err = nil
+			return
+		}
+		if refused && result.Attempts == 1 {
+			result.Status = OrderRejected
+			return
+		}
+		if refused || result.Attempts == placeOrderAttempts {
+			// resubmitting won't help (anymore), but a refused resubmission or the last submission might still
+			// land after it failed. not found otherwise, resubmitting with the same client order id is safe.
+			break
+		}
+	}
+
+	result.Status = OrderUnknown
+	return
+}
+
+// findOrderByClientOrderId reports found == false (and no error) only if the exchange definitely has no order
+// with the given client order id.
+func (c *HttpClient) findOrderByClientOrderId(ctx context.Context, currencyPair string, clOrdId string) (orderId string, found bool, err error) {
+	status, err := c.V2OrderStatusWithContext(ctx, 0, clOrdId, true)
+	if err == nil {
+		return strconv.FormatInt(status.Id, 10), true, nil
+	}
+	if !errors.Is(err, ErrOrderNotFound) {
+		return "", false, err
+	}
+
+	// order status might lag behind, open orders are the source of truth for resting orders
+	if currencyPair == "" {
+		currencyPair = "all"
+	}
+	openOrders, err := c.V2OpenOrdersWithContext(ctx, currencyPair)
+	if err != nil {
+		return "", false, err
+	}
+	for _, order := range openOrders {
+		if order.ClientOrderId == clOrdId {
+			return order.Id, true, nil
+		}
+	}
+	return "", false, nil
+}
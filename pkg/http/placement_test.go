@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeExchange answers order placement, order status and open orders requests.
+type fakeExchange struct {
+	mu         sync.Mutex
+	placements int
+	// response to the n-th placement (once exhausted, the order is accepted)
+	placementResponses []struct {
+		statusCode int
+		body       string
+		accepted   bool // whether the order actually made it onto the book
+		late       bool // whether it only makes it onto the book by the next placement
+	}
+	orders map[string]string // client order id -> order id
+	late   string            // client order id of an order landing late
+}
+
+func (e *fakeExchange) handle(r *http.Request) (int, string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_ = r.ParseForm()
+	clOrdId := r.PostForm.Get("client_order_id")
+
+	switch r.URL.Path {
+	case "/v2/buy/btcusd/":
+		e.placements++
+		if e.late != "" {
+			e.orders[e.late] = "1234"
+			e.late = ""
+		}
+		statusCode, body, accepted := 200, `{"id": "1234", "status": "Open"}`, true
+		if e.placements <= len(e.placementResponses) {
+			resp := e.placementResponses[e.placements-1]
+			statusCode, body, accepted = resp.statusCode, resp.body, resp.accepted
+			if resp.late {
+				e.late = clOrdId
+			}
+		}
+		if accepted {
+			e.orders[clOrdId] = "1234"
+		}
+		return statusCode, body
+	case "/v2/order_status/":
+		if id, exists := e.orders[clOrdId]; exists {
+			return 200, `{"id": ` + id + `, "status": "Open", "client_order_id": "` + clOrdId + `"}`
+		}
+		return 200, `{"status": "error", "reason": "Order not found."}`
+	case "/v2/open_orders/btcusd/":
+		return 200, `[]`
+	}
+	return 404, `{}`
+}
+
+func TestPlaceOrder(t *testing.T) {
+	place := func(c *HttpClient) PlaceOrderFunc {
+		return func(ctx context.Context, clOrdId string) (string, error) {
+			resp, err := c.V2BuyLimitOrderWithContext(ctx, "btcusd", decimal.NewFromInt(1), decimal.NewFromInt(1), decimal.Zero, false, false, clOrdId, nil, nil, false)
+			return resp.Id, err
+		}
+	}
+	type response = struct {
+		statusCode int
+		body       string
+		accepted   bool
+		late       bool
+	}
+	refused := response{200, `{"status": "error", "reason": {"__all__": ["Order with this client order id already exists."]}}`, false, false}
+
+	cases := []struct {
+		name       string
+		responses  []response
+		status     OrderPlacementStatus
+		placements int
+	}{
+		{"accepted", nil, OrderPlaced, 1},
+		{"rejected", []response{{200, `{"status": "error", "reason": {"__all__": ["You have only 1 USD available."]}}`, false, false}}, OrderRejected, 1},
+		{"accepted despite error", []response{{502, `Bad Gateway`, true, false}}, OrderPlaced, 1},
+		{"lost and resubmitted", []response{{502, `Bad Gateway`, false, false}}, OrderPlaced, 2},
+		{"accepted late, resubmit refused", []response{{502, ``, false, true}, refused}, OrderPlaced, 2},
+		{"resubmit refused", []response{{502, ``, false, false}, refused}, OrderUnknown, 2},
+		// the last submission might still land
+		{"never made it", []response{{502, ``, false, false}, {502, ``, false, false}, {504, ``, false, false}}, OrderUnknown, 3},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			exchange := &fakeExchange{placementResponses: tc.responses, orders: make(map[string]string)}
+			server := newSignedTestServer(t, "secret", exchange.handle)
+			c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+			result := c.PlaceOrder(context.Background(), "btcusd", "", place(c))
+			assert.Equal(t, tc.status, result.Status, "%v", result.Err)
+			assert.Equal(t, tc.placements, exchange.placements)
+			assert.Equal(t, tc.placements, result.Attempts)
+			assert.NotEmpty(t, result.ClientOrderId)
+			if tc.status == OrderPlaced {
+				assert.Equal(t, "1234", result.OrderId)
+				assert.NoError(t, result.Err)
+			} else {
+				assert.Error(t, result.Err)
+			}
+		})
+	}
+
+	t.Run("retries enabled", func(t *testing.T) {
+		cases := []struct {
+			name       string
+			responses  []response
+			status     OrderPlacementStatus
+			placements int
+		}{
+			{"retry refused", []response{{502, ``, true, false}, refused}, OrderPlaced, 2},
+			{"rejected", []response{{200, `{"status": "error", "reason": {"__all__": ["You have only 1 USD available."]}}`, false, false}}, OrderRejected, 1},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				exchange := &fakeExchange{placementResponses: tc.responses, orders: make(map[string]string)}
+				server := newSignedTestServer(t, "secret", exchange.handle)
+				policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+				c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), Retries(policy))
+
+				result := c.PlaceOrder(context.Background(), "btcusd", "", place(c))
+				assert.Equal(t, tc.status, result.Status, "%v", result.Err)
+				assert.Equal(t, tc.placements, exchange.placements)
+				assert.Equal(t, 1, result.Attempts)
+			})
+		}
+	})
+
+	t.Run("timed out, accepted after the lookup", func(t *testing.T) {
+		exchange := &fakeExchange{orders: make(map[string]string)}
+		var mu sync.Mutex
+		first := true
+		server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+			mu.Lock()
+			slow := first && r.URL.Path == "/v2/buy/btcusd/"
+			first = first && !slow
+			mu.Unlock()
+			if slow {
+				time.Sleep(300 * time.Millisecond) // lands only after the client gave up and looked it up
+			}
+			return exchange.handle(r)
+		})
+		c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"),
+			CustomHttpClient(&http.Client{Timeout: 100 * time.Millisecond}))
+
+		result := c.PlaceOrder(context.Background(), "btcusd", "", place(c))
+		assert.Equal(t, OrderPlaced, result.Status, "%v", result.Err)
+		assert.Equal(t, 2, result.Attempts)
+	})
+
+	t.Run("unknown when reconciliation fails", func(t *testing.T) {
+		server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+			return 503, ``
+		})
+		c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+		result := c.PlaceOrder(context.Background(), "btcusd", "my-id", place(c))
+		assert.Equal(t, OrderUnknown, result.Status)
+		assert.Equal(t, "my-id", result.ClientOrderId)
+		assert.ErrorIs(t, result.Err, ErrMaintenance)
+	})
+}
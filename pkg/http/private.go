@@ -1,11 +1,14 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -72,6 +75,42 @@ func (st *StringDatetimeFromMicroseconds) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// StringDatetime accepts the API's "2006-01-02 15:04:05" datetimes (in UTC, optionally with fractional seconds),
+// RFC 3339 timestamps and unix timestamps in seconds.
+type StringDatetime time.Time
+
+func (st *StringDatetime) UnmarshalJSON(b []byte) error {
+	var item interface{}
+	if err := json.Unmarshal(b, &item); err != nil {
+		return err
+	}
+	switch v := item.(type) {
+	case float64:
+		*st = StringDatetime(time.Unix(int64(v), 0).UTC())
+	case string:
+		if v == "" {
+			*st = StringDatetime{}
+			return nil
+		}
+		for _, layout := range []string{"2006-01-02 15:04:05.999999999", time.RFC3339Nano} {
+			if t, err := time.Parse(layout, v); err == nil {
+				*st = StringDatetime(t)
+				return nil
+			}
+		}
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid datetime: %q", v)
+		}
+		*st = StringDatetime(time.Unix(seconds, 0).UTC())
+	}
+	return nil
+}
+
+func (st StringDatetime) Time() time.Time {
+	return time.Time(st)
+}
+
 // Contains "private" endpoints whereby we are following the naming here: https://www.bitstamp.net/api/
 
 // Balance
@@ -302,16 +341,93 @@ type V2BalanceResponse struct {
 
 	// fee
 	Fee decimal.Decimal `json:"fee"`
+
+	// all currencies and pairs found in the response, including ones without a field above
+	Currencies map[string]V2CurrencyBalance `json:"-"` // keyed by currency, e.g. "btc"
+	PairFees   map[string]decimal.Decimal   `json:"-"` // keyed by pair, e.g. "btcusd"
+}
+
+type V2CurrencyBalance struct {
+	Balance       decimal.Decimal // <currency>_balance
+	Available     decimal.Decimal // <currency>_available
+	Reserved      decimal.Decimal // <currency>_reserved
+	WithdrawalFee decimal.Decimal // <currency>_withdrawal_fee
+}
+
+func (r *V2BalanceResponse) UnmarshalJSON(b []byte) error {
+	type fixedFields V2BalanceResponse
+	if err := json.Unmarshal(b, (*fixedFields)(r)); err != nil {
+		return err
+	}
+	fields, err := decimalFields(b)
+	if err != nil {
+		return err
+	}
+
+	r.Currencies = make(map[string]V2CurrencyBalance)
+	r.PairFees = make(map[string]decimal.Decimal)
+	for key, value := range fields {
+		// _withdrawal_fee before _fee, the latter is a suffix of the former
+		for _, suffix := range []string{"_balance", "_available", "_reserved", "_withdrawal_fee", "_fee"} {
+			name, found := strings.CutSuffix(key, suffix)
+			if !found || name == "" {
+				continue
+			}
+			if suffix == "_fee" {
+				r.PairFees[name] = value
+				break
+			}
+			balance := r.Currencies[name]
+			switch suffix {
+			case "_balance":
+				balance.Balance = value
+			case "_available":
+				balance.Available = value
+			case "_reserved":
+				balance.Reserved = value
+			case "_withdrawal_fee":
+				balance.WithdrawalFee = value
+			}
+			r.Currencies[name] = balance
+			break
+		}
+	}
+	return nil
+}
+
+// decimalFields returns all values of a JSON object which are decimals (numbers or numeric strings).
+func decimalFields(b []byte) (map[string]decimal.Decimal, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]decimal.Decimal, len(raw))
+	for key, value := range raw {
+		if string(value) == "null" {
+			continue
+		}
+		var d decimal.Decimal
+		if err := json.Unmarshal(value, &d); err == nil {
+			fields[key] = d
+		}
+	}
+	return fields, nil
 }
 
 // POST https://www.bitstamp.net/api/v2/balance/
 // POST https://www.bitstamp.net/api/v2/balance/{currency_pair}/
 func (c *HttpClient) V2Balance(currencyPairOrAll string) (response V2BalanceResponse, err error) {
-	// TODO: validate currency pair
+	return c.V2BalanceWithContext(context.Background(), currencyPairOrAll)
+}
+
+func (c *HttpClient) V2BalanceWithContext(ctx context.Context, currencyPairOrAll string) (response V2BalanceResponse, err error) {
 	if currencyPairOrAll == "all" {
-		err = c.authenticatedFormRequest(&response, "POST", "/v2/balance/", nil, nil)
+		err = c.authenticatedFormRequest(ctx, "V2Balance", &response, "POST", "/v2/balance/", nil, nil)
 	} else {
-		err = c.authenticatedFormRequest(&response, "POST", fmt.Sprintf("/v2/balance/%s/", currencyPairOrAll), nil, nil)
+		if err = c.validateCurrencyPair(ctx, currencyPairOrAll); err != nil {
+			return
+		}
+		err = c.authenticatedFormRequest(ctx, "V2Balance", &response, "POST", fmt.Sprintf("/v2/balance/%s/", currencyPairOrAll), nil, nil)
 	}
 
 	return
@@ -328,17 +444,36 @@ type V2AccountBalancesResponse struct {
 
 // POST https://www.bitstamp.net/api/v2/account_balances/
 func (c *HttpClient) V2AccountBalances() (response []V2AccountBalancesResponse, err error) {
-	err = c.authenticatedFormRequest(&response, "POST", "/v2/account_balances/", nil, nil)
+	return c.V2AccountBalancesWithContext(context.Background())
+}
+
+func (c *HttpClient) V2AccountBalancesWithContext(ctx context.Context) (response []V2AccountBalancesResponse, err error) {
+	err = c.authenticatedFormRequest(ctx, "V2AccountBalances", &response, "POST", "/v2/account_balances/", nil, nil)
 	return
 }
 
 // User transactions
+
+type UserTransactionType string
+
+const (
+	DepositTransaction                  UserTransactionType = "0"
+	WithdrawalTransaction               UserTransactionType = "1"
+	MarketTradeTransaction              UserTransactionType = "2"
+	SubAccountTransferTransaction       UserTransactionType = "14"
+	CreditedWithStakedAssetsTransaction UserTransactionType = "25"
+	SentAssetsToStakingTransaction      UserTransactionType = "26"
+	StakingRewardTransaction            UserTransactionType = "27"
+	ReferralRewardTransaction           UserTransactionType = "32"
+	InterAccountTransferTransaction     UserTransactionType = "35"
+)
+
 type V2UserTransactionsResponse struct {
-	Datetime string          `json:"datetime"`
-	Fee      decimal.Decimal `json:"fee"`
-	Id       int64           `json:"id"`
-	OrderId  int64           `json:"order_id"`
-	Type     string          `json:"type"`
+	Datetime string              `json:"datetime"`
+	Fee      decimal.Decimal     `json:"fee"`
+	Id       int64               `json:"id"`
+	OrderId  int64               `json:"order_id"`
+	Type     UserTransactionType `json:"type"`
 
 	Status string      `json:"status"`
 	Reason interface{} `json:"reason"`
@@ -377,16 +512,169 @@ type V2UserTransactionsResponse struct {
 	Yfi    decimal.Decimal `json:"yfi"`
 	Zrx    decimal.Decimal `json:"zrx"`
 	BtcUsd decimal.Decimal `json:"btc_usd"`
+
+	// all currencies and rates found in the response, including ones without a field above
+	Amounts map[string]decimal.Decimal `json:"-"` // keyed by currency, e.g. "btc"
+	Rates   map[string]decimal.Decimal `json:"-"` // keyed by <base>_<counter>, e.g. "btc_usd"
+}
+
+// keys of a user transaction which are neither amounts nor rates
+var userTransactionFields = map[string]bool{
+	"datetime": true,
+	"fee":      true,
+	"id":       true,
+	"order_id": true,
+	"type":     true,
+	"status":   true,
+	"reason":   true,
+}
+
+func (t *V2UserTransactionsResponse) UnmarshalJSON(b []byte) error {
+	type fixedFields V2UserTransactionsResponse
+	if err := json.Unmarshal(b, (*fixedFields)(t)); err != nil {
+		return err
+	}
+	fields, err := decimalFields(b)
+	if err != nil {
+		return err
+	}
+
+	t.Amounts = make(map[string]decimal.Decimal)
+	t.Rates = make(map[string]decimal.Decimal)
+	for key, value := range fields {
+		if !userTransactionFields[key] && !strings.Contains(key, "_") {
+			t.Amounts[key] = value
+		}
+	}
+	// only <base>_<counter> of two of the amounts is a rate, other keys (e.g. "self_trade_order_id") are ignored
+	for key, value := range fields {
+		base, counter, found := strings.Cut(key, "_")
+		if !found || userTransactionFields[key] {
+			continue
+		}
+		_, hasBase := t.Amounts[base]
+		_, hasCounter := t.Amounts[counter]
+		if hasBase && hasCounter {
+			t.Rates[key] = value
+		}
+	}
+	return nil
+}
+
+type TransactionLeg struct {
+	Currency string
+	Amount   decimal.Decimal // negative for the currency spent
+}
+
+// Legs returns the base and counter currency legs of a trade. ok is false for transactions without a rate, e.g.
+// deposits and withdrawals.
+func (t V2UserTransactionsResponse) Legs() (base, counter TransactionLeg, ok bool) {
+	baseCurrency, counterCurrency, _, ok := t.rate()
+	if !ok {
+		return
+	}
+	base = TransactionLeg{Currency: baseCurrency, Amount: t.Amounts[baseCurrency]}
+	counter = TransactionLeg{Currency: counterCurrency, Amount: t.Amounts[counterCurrency]}
+	return
+}
+
+// Rate returns the rate a trade was executed at, in counter currency per unit of base currency. ok is false for
+// transactions without a rate.
+func (t V2UserTransactionsResponse) Rate() (rate decimal.Decimal, ok bool) {
+	_, _, rate, ok = t.rate()
+	return
+}
+
+func (t V2UserTransactionsResponse) rate() (base, counter string, rate decimal.Decimal, ok bool) {
+	keys := make([]string, 0, len(t.Rates))
+	for key := range t.Rates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		base, counter, found := strings.Cut(key, "_")
+		if !found || base == "" || counter == "" {
+			continue
+		}
+		_, hasBase := t.Amounts[base]
+		_, hasCounter := t.Amounts[counter]
+		if hasBase && hasCounter {
+			return base, counter, t.Rates[key], true
+		}
+	}
+	return
 }
 
-// TODO: add arguments!
+const (
+	maxUserTransactionsOffset = 200000
+	maxUserTransactionsLimit  = 1000
+)
+
+// POST https://www.bitstamp.net/api/v2/user_transactions/
+// POST https://www.bitstamp.net/api/v2/user_transactions/{currency_pair}/
+//
+// Returns the latest 1000 transactions, see V2UserTransactionsPage for the other parameters.
 func (c *HttpClient) V2UserTransactions(currencyPairOrAll string) (response []V2UserTransactionsResponse, err error) {
-	if currencyPairOrAll == "all" {
-		err = c.authenticatedFormRequest(&response, "POST", "/v2/user_transactions/", nil, map[string]string{"limit": "1000"})
-	} else {
-		err = c.authenticatedFormRequest(&response, "POST", fmt.Sprintf("/v2/user_transactions/%s/", currencyPairOrAll), nil, map[string]string{"limit": "1000"})
+	return c.V2UserTransactionsWithContext(context.Background(), currencyPairOrAll)
+}
+
+func (c *HttpClient) V2UserTransactionsWithContext(ctx context.Context, currencyPairOrAll string) (response []V2UserTransactionsResponse, err error) {
+	return c.V2UserTransactionsPageWithContext(ctx, currencyPairOrAll, nil, nil, nil, nil, nil, nil)
+}
+
+// V2UserTransactionsPage is V2UserTransactions with all of the endpoint's parameters. They are optional (nil):
+// offset defaults to 0 (at most 200000), limit to 1000 (at most 1000), sort to descending. Timestamps are unix
+// seconds. To walk the whole history use UserTransactionsIterator.
+func (c *HttpClient) V2UserTransactionsPage(currencyPairOrAll string, offset *int64, limit *int64, sort *Sort, sinceTimestamp *int64, untilTimestamp *int64, sinceId *int64) (response []V2UserTransactionsResponse, err error) {
+	return c.V2UserTransactionsPageWithContext(context.Background(), currencyPairOrAll, offset, limit, sort, sinceTimestamp, untilTimestamp, sinceId)
+}
+
+func (c *HttpClient) V2UserTransactionsPageWithContext(ctx context.Context, currencyPairOrAll string, offset *int64, limit *int64, sort *Sort, sinceTimestamp *int64, untilTimestamp *int64, sinceId *int64) (response []V2UserTransactionsResponse, err error) {
+	if offset == nil {
+		offsetValue := int64(0)
+		offset = &offsetValue
+	}
+	if *offset < 0 || *offset > maxUserTransactionsOffset {
+		err = errors.New("invalid offset")
+		return
+	}
+	if limit == nil {
+		limitValue := int64(maxUserTransactionsLimit)
+		limit = &limitValue
+	}
+	if *limit < 1 || *limit > maxUserTransactionsLimit {
+		err = errors.New("invalid limit")
+		return
+	}
+	if sort == nil {
+		sortValue := Descending
+		sort = &sortValue
+	}
+	urlPath := "/v2/user_transactions/"
+	if currencyPairOrAll != "all" {
+		if err = c.validateCurrencyPair(ctx, currencyPairOrAll); err != nil {
+			return
+		}
+		urlPath = fmt.Sprintf("/v2/user_transactions/%s/", currencyPairOrAll)
+	}
+
+	params := map[string]string{
+		"offset": strconv.FormatInt(*offset, 10),
+		"limit":  strconv.FormatInt(*limit, 10),
+		"sort":   string(*sort),
+	}
+	if sinceTimestamp != nil {
+		params["since_timestamp"] = strconv.FormatInt(*sinceTimestamp, 10)
+	}
+	if untilTimestamp != nil {
+		params["until_timestamp"] = strconv.FormatInt(*untilTimestamp, 10)
+	}
+	if sinceId != nil {
+		params["since_id"] = strconv.FormatInt(*sinceId, 10)
 	}
 
+	err = c.authenticatedFormRequest(ctx, "V2UserTransactions", &response, "POST", urlPath, nil, params)
 	return
 }
 
@@ -420,51 +708,89 @@ type V2CryptoTransactionsResponse struct {
 }
 
 func (c *HttpClient) V2CryptoTransactions(includeIous bool) (response V2CryptoTransactionsResponse, err error) {
+	return c.V2CryptoTransactionsWithContext(context.Background(), includeIous)
+}
+
+func (c *HttpClient) V2CryptoTransactionsWithContext(ctx context.Context, includeIous bool) (response V2CryptoTransactionsResponse, err error) {
 	params := map[string]string{"limit": "1000"}
 	if includeIous {
 		params["include_ious"] = ""
 	}
 
-	err = c.authenticatedFormRequest(&response, "POST", "/v2/crypto-transactions/", nil, params)
+	err = c.authenticatedFormRequest(ctx, "V2CryptoTransactions", &response, "POST", "/v2/crypto-transactions/", nil, params)
 	return
 }
 
 // Crypto Address
 
 type V2CryptoAddressResponse struct {
-	Address string `json:"address"`
-	Error   string `json:"error"`
+	Address        string    `json:"address"`
+	DestinationTag StringInt `json:"destination_tag"` // XRP only, zero if not used
+	MemoId         string    `json:"memo_id"`         // XLM and HBAR only
+	Network        string    `json:"network"`
+	Error          string    `json:"error"`
 }
 
+// V2CryptoAddress returns the deposit address of the currency's default network, see DepositAddress for
+// currencies available on multiple networks.
 func (c *HttpClient) V2CryptoAddress(currency string) (response V2CryptoAddressResponse, err error) {
+	return c.V2CryptoAddressWithContext(context.Background(), currency)
+}
+
+func (c *HttpClient) V2CryptoAddressWithContext(ctx context.Context, currency string) (response V2CryptoAddressResponse, err error) {
+	return c.V2CryptoAddressOnNetworkWithContext(ctx, currency, "")
+}
+
+func (c *HttpClient) V2CryptoAddressOnNetwork(currency string, network string) (response V2CryptoAddressResponse, err error) {
+	return c.V2CryptoAddressOnNetworkWithContext(context.Background(), currency, network)
+}
+
+func (c *HttpClient) V2CryptoAddressOnNetworkWithContext(ctx context.Context, currency string, network string) (response V2CryptoAddressResponse, err error) {
 	urlPath := fmt.Sprintf("/v2/%s_address/", currency)
-	err = c.authenticatedFormRequest(&response, "POST", urlPath, nil, nil)
+	var params map[string]string
+	if network != "" {
+		params = map[string]string{"network": network}
+	}
+	err = c.authenticatedFormRequest(ctx, "V2CryptoAddress", &response, "POST", urlPath, nil, params)
+	if err == nil && response.Network == "" {
+		response.Network = network
+	}
 	return
 }
 
 // Withdrawal Requests
 
+// V2WithdrawalRequestsResponse is a single withdrawal request. Id, Type and Status used to be strings, which failed
+// to decode the numbers the API returns; use strconv.FormatInt(int64(r.Id), 10) and r.Status.String() where
+// strings are needed.
 type V2WithdrawalRequestsResponse struct {
-	Id       string          `json:"id"`
-	Datetime string          `json:"datetime"`
-	Type     string          `json:"type"`
-	Currency string          `json:"currency"`
-	Amount   decimal.Decimal `json:"amount"`
-	Status   string          `json:"status"`
-	Txid     string          `json:"txid"`
-	Reason   interface{}     `json:"reason"`
+	Id            StringInt        `json:"id"`
+	Datetime      string           `json:"datetime"`
+	Type          StringInt        `json:"type"` // 0 (SEPA), 1 (bitcoin), 2 (WIRE), 14 (XRP), ...
+	Currency      string           `json:"currency"`
+	Amount        decimal.Decimal  `json:"amount"`
+	Status        WithdrawalStatus `json:"status"`
+	Address       string           `json:"address"`
+	Network       string           `json:"network"`
+	TransactionId string           `json:"transaction_id"`
+	Txid          string           `json:"txid"`
+	Reason        interface{}      `json:"reason"`
 }
 
 func (c *HttpClient) V2WithdrawalRequests(withdrawalId int64, timeDelta string) (response []V2WithdrawalRequestsResponse, err error) {
+	return c.V2WithdrawalRequestsWithContext(context.Background(), withdrawalId, timeDelta)
+}
+
+func (c *HttpClient) V2WithdrawalRequestsWithContext(ctx context.Context, withdrawalId int64, timeDelta string) (response []V2WithdrawalRequestsResponse, err error) {
 	params := map[string]string{"offset": "0", "limit": "1000"}
 	if withdrawalId != 0 {
 		params["id"] = fmt.Sprintf("%d", withdrawalId)
 	}
 	if timeDelta != "" {
-		params["timedelta"] = ""
+		params["timedelta"] = timeDelta
 	}
 
-	err = c.authenticatedFormRequest(&response, "POST", "/v2/withdrawal-requests/", nil, params)
+	err = c.authenticatedFormRequest(ctx, "V2WithdrawalRequests", &response, "POST", "/v2/withdrawal-requests/", nil, params)
 	return
 }
 
@@ -477,7 +803,11 @@ type V2WithdrawalFeesResponse struct {
 }
 
 func (c *HttpClient) V2WithdrawalFees() (response []V2WithdrawalFeesResponse, err error) {
-	err = c.authenticatedFormRequest(&response, "POST", "/v2/fees/withdrawal/", nil, nil)
+	return c.V2WithdrawalFeesWithContext(context.Background())
+}
+
+func (c *HttpClient) V2WithdrawalFeesWithContext(ctx context.Context) (response []V2WithdrawalFeesResponse, err error) {
+	err = c.authenticatedFormRequest(ctx, "V2WithdrawalFees", &response, "POST", "/v2/fees/withdrawal/", nil, nil)
 	return
 }
 
@@ -495,7 +825,11 @@ type V2TradingFeesResponse struct {
 }
 
 func (c *HttpClient) V2TradingFees() (response []V2TradingFeesResponse, err error) {
-	err = c.authenticatedFormRequest(&response, "POST", "/v2/fees/trading/", nil, nil)
+	return c.V2TradingFeesWithContext(context.Background())
+}
+
+func (c *HttpClient) V2TradingFeesWithContext(ctx context.Context) (response []V2TradingFeesResponse, err error) {
+	err = c.authenticatedFormRequest(ctx, "V2TradingFees", &response, "POST", "/v2/fees/trading/", nil, nil)
 	return
 }
 
@@ -517,8 +851,12 @@ type V2OpenOrdersResponse struct {
 // POST https://www.bitstamp.net/api/v2/open_orders/all/
 // POST https://www.bitstamp.net/api/v2/open_orders/{currency_pair}
 func (c *HttpClient) V2OpenOrders(currencyPairOrAll string) (response []V2OpenOrdersResponse, err error) {
+	return c.V2OpenOrdersWithContext(context.Background(), currencyPairOrAll)
+}
+
+func (c *HttpClient) V2OpenOrdersWithContext(ctx context.Context, currencyPairOrAll string) (response []V2OpenOrdersResponse, err error) {
 	urlPath := fmt.Sprintf("/v2/open_orders/%s/", currencyPairOrAll)
-	err = c.authenticatedFormRequest(&response, "POST", urlPath, nil, nil)
+	err = c.authenticatedFormRequest(ctx, "V2OpenOrders", &response, "POST", urlPath, nil, nil)
 
 	return
 }
@@ -549,8 +887,14 @@ type V2OrderStatusResponse struct {
 
 // POST https://www.bitstamp.net/api/v2/order_status/
 func (c *HttpClient) V2OrderStatus(orderId int64, clOrdId string, omitTx bool) (response V2OrderStatusResponse, err error) {
-	params := map[string]string{
-		"id": fmt.Sprintf("%d", orderId),
+	return c.V2OrderStatusWithContext(context.Background(), orderId, clOrdId, omitTx)
+}
+
+func (c *HttpClient) V2OrderStatusWithContext(ctx context.Context, orderId int64, clOrdId string, omitTx bool) (response V2OrderStatusResponse, err error) {
+	// either of the ids identifies the order, the other one can be left empty (0)
+	params := make(map[string]string)
+	if orderId != 0 {
+		params["id"] = fmt.Sprintf("%d", orderId)
 	}
 	if clOrdId != "" {
 		params["client_order_id"] = clOrdId
@@ -559,15 +903,7 @@ func (c *HttpClient) V2OrderStatus(orderId int64, clOrdId string, omitTx bool) (
 		params["omit_transactions"] = "true"
 	}
 
-	err = c.authenticatedFormRequest(&response, "POST", "/v2/order_status/", nil, params)
-	if err != nil {
-		return
-	}
-
-	if response.Status == "error" {
-		err = fmt.Errorf("error: %v", response.Reason)
-	}
-
+	err = c.authenticatedFormRequest(ctx, "V2OrderStatus", &response, "POST", "/v2/order_status/", nil, params)
 	return
 }
 
@@ -584,11 +920,103 @@ type V2CancelOrderResponse struct {
 }
 
 func (c *HttpClient) V2CancelOrder(orderId int64) (response V2CancelOrderResponse, err error) {
-	err = c.authenticatedFormRequest(&response, "POST", "/v2/cancel_order/", nil, map[string]string{"id": fmt.Sprintf("%d", orderId)})
+	return c.V2CancelOrderWithContext(context.Background(), orderId)
+}
+
+func (c *HttpClient) V2CancelOrderWithContext(ctx context.Context, orderId int64) (response V2CancelOrderResponse, err error) {
+	err = c.authenticatedFormRequest(ctx, "V2CancelOrder", &response, "POST", "/v2/cancel_order/", nil, map[string]string{"id": fmt.Sprintf("%d", orderId)})
+	return
+}
+
+// POST https://www.bitstamp.net/api/v2/cancel_order/ with client_order_id instead of id
+func (c *HttpClient) V2CancelOrderByClientOrderId(clOrdId string) (response V2CancelOrderResponse, err error) {
+	return c.V2CancelOrderByClientOrderIdWithContext(context.Background(), clOrdId)
+}
+
+func (c *HttpClient) V2CancelOrderByClientOrderIdWithContext(ctx context.Context, clOrdId string) (response V2CancelOrderResponse, err error) {
+	if clOrdId == "" {
+		err = errors.New("clOrdId is required")
+		return
+	}
+	err = c.authenticatedFormRequest(ctx, "V2CancelOrderByClientOrderId", &response, "POST", "/v2/cancel_order/", nil, map[string]string{"client_order_id": clOrdId})
 	return
 }
 
+//
 // Cancel all orders
+//
+
+type V2CanceledOrder struct {
+	Id            int64           `json:"id"`
+	Amount        decimal.Decimal `json:"amount"`
+	Price         decimal.Decimal `json:"price"`
+	Type          uint8           `json:"type"` // 0 (buy) or 1 (sell)
+	CurrencyPair  string          `json:"currency_pair"`
+	ClientOrderId string          `json:"client_order_id"`
+}
+
+type V2CancelAllOrdersResponse struct {
+	Canceled []V2CanceledOrder `json:"canceled"`
+	Failed   []V2CanceledOrder `json:"failed"`
+	Success  bool              `json:"success"`
+}
+
+// POST https://www.bitstamp.net/api/v2/cancel_all_orders/
+// POST https://www.bitstamp.net/api/v2/cancel_all_orders/{currency_pair}/
+func (c *HttpClient) V2CancelAllOrders(currencyPairOrAll string) (response V2CancelAllOrdersResponse, err error) {
+	return c.V2CancelAllOrdersWithContext(context.Background(), currencyPairOrAll)
+}
+
+func (c *HttpClient) V2CancelAllOrdersWithContext(ctx context.Context, currencyPairOrAll string) (response V2CancelAllOrdersResponse, err error) {
+	urlPath := "/v2/cancel_all_orders/"
+	if currencyPairOrAll != "all" {
+		if err = c.validateCurrencyPair(ctx, currencyPairOrAll); err != nil {
+			return
+		}
+		urlPath = fmt.Sprintf("/v2/cancel_all_orders/%s/", currencyPairOrAll)
+	}
+
+	err = c.authenticatedFormRequest(ctx, "V2CancelAllOrders", &response, "POST", urlPath, nil, nil)
+	return
+}
+
+//
+// Replace order
+//
+
+// POST https://www.bitstamp.net/api/v2/replace_order/
+func (c *HttpClient) V2ReplaceOrder(orderId int64, origClOrdId string, price, amount decimal.Decimal, clOrdId string) (response V2LimitOrderResponse, err error) {
+	return c.V2ReplaceOrderWithContext(context.Background(), orderId, origClOrdId, price, amount, clOrdId)
+}
+
+// V2ReplaceOrderWithContext atomically replaces a resting order with a new one at the given price and amount.
+// The order is identified by either orderId or origClOrdId, the other one can be left empty (0). clOrdId is the
+// (optional) client order id of the new order.
+func (c *HttpClient) V2ReplaceOrderWithContext(ctx context.Context, orderId int64, origClOrdId string, price, amount decimal.Decimal, clOrdId string) (response V2LimitOrderResponse, err error) {
+	params := map[string]string{
+		"amount": amount.String(),
+		"price":  price.String(),
+	}
+	switch {
+	case orderId != 0:
+		params["id"] = fmt.Sprintf("%d", orderId)
+	case origClOrdId != "":
+		params["orig_client_order_id"] = origClOrdId
+	default:
+		err = errors.New("either orderId or origClOrdId is required")
+		return
+	}
+	if clOrdId != "" {
+		params["client_order_id"] = clOrdId
+	}
+
+	err = c.authenticatedFormRequest(ctx, "V2ReplaceOrder", &response, "POST", "/v2/replace_order/", nil, params)
+	if err != nil {
+		err = fmt.Errorf("error replacing order (%s @ %s): %w", amount, price, err)
+	}
+	return
+}
+
 // Buy limit order
 // Sell limit order
 
@@ -600,12 +1028,31 @@ type V2LimitOrderResponse struct {
 	Type       string           `json:"type"`
 	Price      decimal.Decimal  `json:"price"`
 	Amount     decimal.Decimal  `json:"amount"`
+	LimitPrice decimal.Decimal  `json:"limit_price"` // zero unless returned by the exchange
 	Status     string           `json:"status"`
 	Reason     interface{}      `json:"reason"`
 	Leverage   *decimal.Decimal `json:"leverage"`
 	MarginMode *MarginMode      `json:"margin_mode"`
 }
 
+// validateLimitPrice checks the (optional) limit price of a limit order. Once a buy order is executed, a sell
+// order at limit price is placed (and vice versa), so it only makes sense above the buy (below the sell) price.
+func validateLimitPrice(side OrderSide, price, limitPrice decimal.Decimal) error {
+	if limitPrice.IsZero() {
+		return nil
+	}
+	if limitPrice.IsNegative() {
+		return fmt.Errorf("limit price must be positive: %s", limitPrice)
+	}
+	if side == Buy && !limitPrice.GreaterThan(price) {
+		return fmt.Errorf("limit price %s of a buy order must be above price %s", limitPrice, price)
+	}
+	if side == Sell && !limitPrice.LessThan(price) {
+		return fmt.Errorf("limit price %s of a sell order must be below price %s", limitPrice, price)
+	}
+	return nil
+}
+
 type MarginMode string
 
 const (
@@ -613,13 +1060,13 @@ const (
 	Isolated MarginMode = "ISOLATED"
 )
 
-func (c *HttpClient) v2LimitOrder(side, currencyPair string, price, amount, limitPrice decimal.Decimal, dailyOrder, iocOrder bool, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2LimitOrderResponse, err error) {
+func (c *HttpClient) v2LimitOrder(ctx context.Context, endpoint, side, currencyPair string, price, amount, limitPrice decimal.Decimal, dailyOrder, iocOrder bool, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2LimitOrderResponse, err error) {
 	urlPath := fmt.Sprintf("/v2/%s/%s/", side, currencyPair)
 
-	if c.autoRounding {
-		// TODO: we probably need "smarter" (stingier?) rounding here...
-		amount = amount.Round(roundings[currencyPair].Base)
-		price = price.Round(roundings[currencyPair].Counter)
+	rounded := c.roundOrder(ctx, OrderRequest{Type: LimitOrder, Side: OrderSide(side), CurrencyPair: currencyPair, Price: price, Amount: amount, LimitPrice: limitPrice})
+	price, amount, limitPrice = rounded.Price, rounded.Amount, rounded.LimitPrice
+	if err = validateLimitPrice(OrderSide(side), price, limitPrice); err != nil {
+		return
 	}
 
 	params := map[string]string{
@@ -645,26 +1092,31 @@ func (c *HttpClient) v2LimitOrder(side, currencyPair string, price, amount, limi
 	if reduceOnly {
 		params["reduce_only"] = "True"
 	}
-	// TODO: limitPrice !
-
-	err = c.authenticatedFormRequest(&response, "POST", urlPath, nil, params)
-	if err != nil {
-		return
+	if !limitPrice.IsZero() {
+		params["limit_price"] = limitPrice.String()
 	}
 
-	if response.Status == "error" {
-		err = fmt.Errorf("error placing limit %s (%s @ %s): %v", side, amount, price, response.Reason)
+	err = c.authenticatedFormRequest(ctx, endpoint, &response, "POST", urlPath, nil, params)
+	if err != nil {
+		err = fmt.Errorf("error placing limit %s (%s @ %s): %w", side, amount, price, err)
 	}
-
 	return
 }
 
 func (c *HttpClient) V2BuyLimitOrder(currencyPair string, price, amount, limitPrice decimal.Decimal, dailyOrder, iocOrder bool, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2LimitOrderResponse, err error) {
-	return c.v2LimitOrder("buy", currencyPair, price, amount, limitPrice, dailyOrder, iocOrder, clOrdId, marginMode, leverage, reduceOnly)
+	return c.V2BuyLimitOrderWithContext(context.Background(), currencyPair, price, amount, limitPrice, dailyOrder, iocOrder, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2BuyLimitOrderWithContext(ctx context.Context, currencyPair string, price, amount, limitPrice decimal.Decimal, dailyOrder, iocOrder bool, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2LimitOrderResponse, err error) {
+	return c.v2LimitOrder(ctx, "V2BuyLimitOrder", "buy", currencyPair, price, amount, limitPrice, dailyOrder, iocOrder, clOrdId, marginMode, leverage, reduceOnly)
 }
 
 func (c *HttpClient) V2SellLimitOrder(currencyPair string, price, amount, limitPrice decimal.Decimal, dailyOrder, iocOrder bool, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2LimitOrderResponse, err error) {
-	return c.v2LimitOrder("sell", currencyPair, price, amount, limitPrice, dailyOrder, iocOrder, clOrdId, marginMode, leverage, reduceOnly)
+	return c.V2SellLimitOrderWithContext(context.Background(), currencyPair, price, amount, limitPrice, dailyOrder, iocOrder, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2SellLimitOrderWithContext(ctx context.Context, currencyPair string, price, amount, limitPrice decimal.Decimal, dailyOrder, iocOrder bool, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2LimitOrderResponse, err error) {
+	return c.v2LimitOrder(ctx, "V2SellLimitOrder", "sell", currencyPair, price, amount, limitPrice, dailyOrder, iocOrder, clOrdId, marginMode, leverage, reduceOnly)
 }
 
 type V2MarketOrderResponse struct {
@@ -686,8 +1138,9 @@ type V2MarketOrderResponse struct {
 	Status          string          `json:"status"`
 }
 
-func (c *HttpClient) v2MarketOrder(side, currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+func (c *HttpClient) v2MarketOrder(ctx context.Context, endpoint, side, currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
 	urlPath := fmt.Sprintf("/v2/%s/market/%s/", side, currencyPair)
+	amount = c.roundOrder(ctx, NewMarketOrder(OrderSide(side), currencyPair, amount)).Amount
 
 	data := make(map[string]string)
 	data["amount"] = amount.String()
@@ -704,25 +1157,28 @@ func (c *HttpClient) v2MarketOrder(side, currencyPair string, amount decimal.Dec
 		data["reduce_only"] = "True"
 	}
 
-	err = c.authenticatedFormRequest(&response, "POST", urlPath, nil, data)
+	err = c.authenticatedFormRequest(ctx, endpoint, &response, "POST", urlPath, nil, data)
 	if err != nil {
-		return
-	}
-
-	if response.Status == "error" {
-		err = fmt.Errorf("error placing market %s (for %s): %v", side, amount, response.Reason)
-		return
+		err = fmt.Errorf("error placing market %s (for %s): %w", side, amount, err)
 	}
 
 	return
 }
 
 func (c *HttpClient) V2BuyMarketOrder(currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
-	return c.v2MarketOrder("buy", currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+	return c.V2BuyMarketOrderWithContext(context.Background(), currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2BuyMarketOrderWithContext(ctx context.Context, currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.v2MarketOrder(ctx, "V2BuyMarketOrder", "buy", currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
 }
 
 func (c *HttpClient) V2SellMarketOrder(currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
-	return c.v2MarketOrder("sell", currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+	return c.V2SellMarketOrderWithContext(context.Background(), currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2SellMarketOrderWithContext(ctx context.Context, currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.v2MarketOrder(ctx, "V2SellMarketOrder", "sell", currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
 }
 
 type V2InstantOrderResponse struct {
@@ -738,10 +1194,11 @@ type V2InstantOrderResponse struct {
 	MarginMode *MarginMode      `json:"margin_mode"`
 }
 
-func (c *HttpClient) v2InstantOrder(side, currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2InstantOrderResponse, err error) {
+func (c *HttpClient) v2InstantOrder(ctx context.Context, endpoint, side, currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2InstantOrderResponse, err error) {
 	urlPath := fmt.Sprintf("/v2/%s/instant/%s/", side, currencyPair)
+	amount = c.roundOrder(ctx, NewInstantOrder(OrderSide(side), currencyPair, amount)).Amount
 
-	var data map[string]string
+	data := make(map[string]string)
 	data["amount"] = amount.String()
 	if clOrdId != "" {
 		data["client_order_id"] = clOrdId
@@ -756,25 +1213,95 @@ func (c *HttpClient) v2InstantOrder(side, currencyPair string, amount decimal.De
 		data["reduce_only"] = "True"
 	}
 
-	err = c.authenticatedFormRequest(&response, "POST", urlPath, nil, data)
+	err = c.authenticatedFormRequest(ctx, endpoint, &response, "POST", urlPath, nil, data)
 	if err != nil {
-		return
-	}
-
-	if response.Status == "error" {
-		err = fmt.Errorf("error placing market %s (for %s): %v", side, amount, response.Reason)
-		return
+		err = fmt.Errorf("error placing instant %s (for %s): %w", side, amount, err)
 	}
 
 	return
 }
 
 func (c *HttpClient) V2BuyInstantOrder(currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2InstantOrderResponse, err error) {
-	return c.v2InstantOrder("buy", currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+	return c.V2BuyInstantOrderWithContext(context.Background(), currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2BuyInstantOrderWithContext(ctx context.Context, currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2InstantOrderResponse, err error) {
+	return c.v2InstantOrder(ctx, "V2BuyInstantOrder", "buy", currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
 }
 
 func (c *HttpClient) V2SellInstantOrder(currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2InstantOrderResponse, err error) {
-	return c.v2InstantOrder("sell", currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+	return c.V2SellInstantOrderWithContext(context.Background(), currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2SellInstantOrderWithContext(ctx context.Context, currencyPair string, amount decimal.Decimal, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2InstantOrderResponse, err error) {
+	return c.v2InstantOrder(ctx, "V2SellInstantOrder", "sell", currencyPair, amount, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+// v2StopOrder places a stop, stop-limit or trailing stop order. Amount and prices are validated against the pair's
+// decimals (and rounded first with AutoRoundingEnabled).
+func (c *HttpClient) v2StopOrder(ctx context.Context, endpoint string, order OrderRequest, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	order.Trigger = trigger
+	order.ClientOrderId = clOrdId
+	order.MarginMode = marginMode
+	order.Leverage = leverage
+	order.ReduceOnly = reduceOnly
+
+	err = c.submitOrder(ctx, endpoint, order, &response)
+	return
+}
+
+func (c *HttpClient) V2BuyStopOrder(currencyPair string, stopPrice, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.V2BuyStopOrderWithContext(context.Background(), currencyPair, stopPrice, amount, trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2BuyStopOrderWithContext(ctx context.Context, currencyPair string, stopPrice, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.v2StopOrder(ctx, "V2BuyStopOrder", NewStopOrder(Buy, currencyPair, stopPrice, amount), trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2SellStopOrder(currencyPair string, stopPrice, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.V2SellStopOrderWithContext(context.Background(), currencyPair, stopPrice, amount, trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2SellStopOrderWithContext(ctx context.Context, currencyPair string, stopPrice, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.v2StopOrder(ctx, "V2SellStopOrder", NewStopOrder(Sell, currencyPair, stopPrice, amount), trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2BuyStopLimitOrder(currencyPair string, stopPrice, price, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.V2BuyStopLimitOrderWithContext(context.Background(), currencyPair, stopPrice, price, amount, trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2BuyStopLimitOrderWithContext(ctx context.Context, currencyPair string, stopPrice, price, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.v2StopOrder(ctx, "V2BuyStopLimitOrder", NewStopLimitOrder(Buy, currencyPair, stopPrice, price, amount), trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2SellStopLimitOrder(currencyPair string, stopPrice, price, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.V2SellStopLimitOrderWithContext(context.Background(), currencyPair, stopPrice, price, amount, trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2SellStopLimitOrderWithContext(ctx context.Context, currencyPair string, stopPrice, price, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.v2StopOrder(ctx, "V2SellStopLimitOrder", NewStopLimitOrder(Sell, currencyPair, stopPrice, price, amount), trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+// activationPrice is optional (zero), the trailing stop is active right away then
+func (c *HttpClient) V2BuyTrailingStopOrder(currencyPair string, trailingDelta, activationPrice, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.V2BuyTrailingStopOrderWithContext(context.Background(), currencyPair, trailingDelta, activationPrice, amount, trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2BuyTrailingStopOrderWithContext(ctx context.Context, currencyPair string, trailingDelta, activationPrice, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	order := NewTrailingStopOrder(Buy, currencyPair, trailingDelta, amount)
+	order.ActivationPrice = activationPrice
+	return c.v2StopOrder(ctx, "V2BuyTrailingStopOrder", order, trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+// activationPrice is optional (zero), the trailing stop is active right away then
+func (c *HttpClient) V2SellTrailingStopOrder(currencyPair string, trailingDelta, activationPrice, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	return c.V2SellTrailingStopOrderWithContext(context.Background(), currencyPair, trailingDelta, activationPrice, amount, trigger, clOrdId, marginMode, leverage, reduceOnly)
+}
+
+func (c *HttpClient) V2SellTrailingStopOrderWithContext(ctx context.Context, currencyPair string, trailingDelta, activationPrice, amount decimal.Decimal, trigger TriggerType, clOrdId string, marginMode *MarginMode, leverage *decimal.Decimal, reduceOnly bool) (response V2MarketOrderResponse, err error) {
+	order := NewTrailingStopOrder(Sell, currencyPair, trailingDelta, amount)
+	order.ActivationPrice = activationPrice
+	return c.v2StopOrder(ctx, "V2SellTrailingStopOrder", order, trigger, clOrdId, marginMode, leverage, reduceOnly)
 }
 
 type MarketSide string
@@ -815,12 +1342,16 @@ type V2DerivativesOpenPosition struct {
 }
 
 func (c *HttpClient) V2DerivativesOpenPositions(marketSymbol *string) (response []V2DerivativesOpenPosition, err error) {
+	return c.V2DerivativesOpenPositionsWithContext(context.Background(), marketSymbol)
+}
+
+func (c *HttpClient) V2DerivativesOpenPositionsWithContext(ctx context.Context, marketSymbol *string) (response []V2DerivativesOpenPosition, err error) {
 	urlPath := "/v2/open_positions/"
 	if marketSymbol != nil {
 		urlPath = fmt.Sprintf("%s%s/", urlPath, *marketSymbol)
 	}
 
-	err = c.authenticatedFormRequest(&response, "GET", urlPath, nil, nil)
+	err = c.authenticatedFormRequest(ctx, "V2DerivativesOpenPositions", &response, "GET", urlPath, nil, nil)
 	if err != nil {
 		return
 	}
@@ -870,6 +1401,10 @@ type V2DerivativesOpenPositionResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesClosePosition(positionId string) (response V2DerivativesOpenPositionResponse, err error) {
+	return c.V2DerivativesClosePositionWithContext(context.Background(), positionId)
+}
+
+func (c *HttpClient) V2DerivativesClosePositionWithContext(ctx context.Context, positionId string) (response V2DerivativesOpenPositionResponse, err error) {
 	urlPath := "/v2/close_position/"
 	if positionId == "" {
 		err = errors.New("positionId is required")
@@ -879,7 +1414,7 @@ func (c *HttpClient) V2DerivativesClosePosition(positionId string) (response V2D
 		PositionId: positionId,
 	}
 
-	err = c.authenticatedJsonRequest(&response, "POST", urlPath, nil, requestPayload)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesClosePosition", &response, "POST", urlPath, nil, requestPayload)
 	if err != nil {
 		return
 	}
@@ -942,6 +1477,10 @@ type V2DerivativesOpenPositionsResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesClosePositions(orderType ClosePositionOrderType, marginMode *MarginMode, market *string) (response V2DerivativesOpenPositionsResponse, err error) {
+	return c.V2DerivativesClosePositionsWithContext(context.Background(), orderType, marginMode, market)
+}
+
+func (c *HttpClient) V2DerivativesClosePositionsWithContext(ctx context.Context, orderType ClosePositionOrderType, marginMode *MarginMode, market *string) (response V2DerivativesOpenPositionsResponse, err error) {
 	urlPath := "/v2/close_positions/"
 	requestPayload := V2DerivativesOpenPositionsRequest{
 		OrderType:  orderType,
@@ -949,7 +1488,7 @@ func (c *HttpClient) V2DerivativesClosePositions(orderType ClosePositionOrderTyp
 		Market:     market,
 	}
 
-	err = c.authenticatedJsonRequest(&response, "POST", urlPath, nil, requestPayload)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesClosePositions", &response, "POST", urlPath, nil, requestPayload)
 	if err != nil {
 		return
 	}
@@ -974,9 +1513,13 @@ type V2DerivativesMarginInfoResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesMarginInfo() (response V2DerivativesMarginInfoResponse, err error) {
+	return c.V2DerivativesMarginInfoWithContext(context.Background())
+}
+
+func (c *HttpClient) V2DerivativesMarginInfoWithContext(ctx context.Context) (response V2DerivativesMarginInfoResponse, err error) {
 	urlPath := "/v2/margin_info/"
 
-	err = c.authenticatedJsonRequest(&response, "GET", urlPath, nil, nil)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesMarginInfo", &response, "GET", urlPath, nil, nil)
 	if err != nil {
 		return
 	}
@@ -1012,6 +1555,10 @@ type V2DerivativesPositionsHistoryListResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesPositionsHistoryList(marketSymbol *string, sort *Sort, page *int64, perPage *int64) (response []V2DerivativesPositionsHistoryListResponse, err error) {
+	return c.V2DerivativesPositionsHistoryListWithContext(context.Background(), marketSymbol, sort, page, perPage)
+}
+
+func (c *HttpClient) V2DerivativesPositionsHistoryListWithContext(ctx context.Context, marketSymbol *string, sort *Sort, page *int64, perPage *int64) (response []V2DerivativesPositionsHistoryListResponse, err error) {
 	if sort == nil {
 		sortValue := Descending
 		sort = &sortValue
@@ -1031,7 +1578,7 @@ func (c *HttpClient) V2DerivativesPositionsHistoryList(marketSymbol *string, sor
 		urlParams.Set("per_page", strconv.FormatInt(*perPage, 10))
 	}
 
-	err = c.authenticatedJsonRequest(&response, "GET", urlPath, &urlParams, nil)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesPositionsHistoryList", &response, "GET", urlPath, &urlParams, nil)
 	if err != nil {
 		return
 	}
@@ -1066,6 +1613,10 @@ type V2DerivativesPositionsSettlementTransactionListResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesPositionsSettlementTransactionList(marketTransactionId *string, offset *int64, limit *int64, sort *Sort, sinceTimestamp *int64, untilTimestamp *int64, sinceId *int64) (response []V2DerivativesPositionsSettlementTransactionListResponse, err error) {
+	return c.V2DerivativesPositionsSettlementTransactionListWithContext(context.Background(), marketTransactionId, offset, limit, sort, sinceTimestamp, untilTimestamp, sinceId)
+}
+
+func (c *HttpClient) V2DerivativesPositionsSettlementTransactionListWithContext(ctx context.Context, marketTransactionId *string, offset *int64, limit *int64, sort *Sort, sinceTimestamp *int64, untilTimestamp *int64, sinceId *int64) (response []V2DerivativesPositionsSettlementTransactionListResponse, err error) {
 	if offset == nil {
 		offsetValue := int64(0)
 		offset = &offsetValue
@@ -1104,7 +1655,7 @@ func (c *HttpClient) V2DerivativesPositionsSettlementTransactionList(marketTrans
 		urlParams.Set("since_id", strconv.FormatInt(*sinceId, 10))
 	}
 
-	err = c.authenticatedJsonRequest(&response, "GET", urlPath, &urlParams, nil)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesPositionsSettlementTransactionList", &response, "GET", urlPath, &urlParams, nil)
 	if err != nil {
 		return
 	}
@@ -1124,6 +1675,10 @@ type V2DerivativesAdjustCollateralValueForPositionResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesAdjustCollateralValueForPosition(positionId string, newAmount decimal.Decimal) (response V2DerivativesAdjustCollateralValueForPositionResponse, err error) {
+	return c.V2DerivativesAdjustCollateralValueForPositionWithContext(context.Background(), positionId, newAmount)
+}
+
+func (c *HttpClient) V2DerivativesAdjustCollateralValueForPositionWithContext(ctx context.Context, positionId string, newAmount decimal.Decimal) (response V2DerivativesAdjustCollateralValueForPositionResponse, err error) {
 	if positionId == "" {
 		err = errors.New("positionId is empty")
 		return
@@ -1135,7 +1690,7 @@ func (c *HttpClient) V2DerivativesAdjustCollateralValueForPosition(positionId st
 
 	urlPath := "/v2/adjust_position_collateral/"
 
-	err = c.authenticatedJsonRequest(&response, "POST", urlPath, nil, requestPayload)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesAdjustCollateralValueForPosition", &response, "POST", urlPath, nil, requestPayload)
 	if err != nil {
 		return
 	}
@@ -1149,9 +1704,13 @@ type V2DerivativesCollateralCurrenciesResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesCollateralCurrencies() (response []V2DerivativesCollateralCurrenciesResponse, err error) {
+	return c.V2DerivativesCollateralCurrenciesWithContext(context.Background())
+}
+
+func (c *HttpClient) V2DerivativesCollateralCurrenciesWithContext(ctx context.Context) (response []V2DerivativesCollateralCurrenciesResponse, err error) {
 	urlPath := "/v2/collateral_currencies/"
 
-	err = c.authenticatedJsonRequest(&response, "GET", urlPath, nil, nil)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesCollateralCurrencies", &response, "GET", urlPath, nil, nil)
 	if err != nil {
 		return
 	}
@@ -1167,12 +1726,16 @@ type V2DerivativesLeverageSettingsListResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesLeverageSettingsList(marginMode MarginMode, market string) (response []V2DerivativesLeverageSettingsListResponse, err error) {
+	return c.V2DerivativesLeverageSettingsListWithContext(context.Background(), marginMode, market)
+}
+
+func (c *HttpClient) V2DerivativesLeverageSettingsListWithContext(ctx context.Context, marginMode MarginMode, market string) (response []V2DerivativesLeverageSettingsListResponse, err error) {
 	urlPath := "/v2/leverage_settings/"
 	urlParams := make(url.Values)
 	urlParams.Set("margin_mode", string(marginMode))
 	urlParams.Set("market", market)
 
-	err = c.authenticatedJsonRequest(&response, "GET", urlPath, &urlParams, nil)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesLeverageSettingsList", &response, "GET", urlPath, &urlParams, nil)
 	if err != nil {
 		return
 	}
@@ -1194,6 +1757,10 @@ type V2DerivativesUpdateLeverageSettingWithOverrideResponse struct {
 }
 
 func (c *HttpClient) V2DerivativesUpdateLeverageSettingWithOverride(leverage decimal.Decimal, marginMode MarginMode, market string) (response V2DerivativesUpdateLeverageSettingWithOverrideResponse, err error) {
+	return c.V2DerivativesUpdateLeverageSettingWithOverrideWithContext(context.Background(), leverage, marginMode, market)
+}
+
+func (c *HttpClient) V2DerivativesUpdateLeverageSettingWithOverrideWithContext(ctx context.Context, leverage decimal.Decimal, marginMode MarginMode, market string) (response V2DerivativesUpdateLeverageSettingWithOverrideResponse, err error) {
 	urlPath := "/v2/leverage_settings/"
 	requestPayload := V2DerivativesUpdateLeverageSettingWithOverrideRequest{
 		Leverage:   leverage,
@@ -1201,7 +1768,7 @@ func (c *HttpClient) V2DerivativesUpdateLeverageSettingWithOverride(leverage dec
 		Market:     market,
 	}
 
-	err = c.authenticatedJsonRequest(&response, "POST", urlPath, nil, requestPayload)
+	err = c.authenticatedJsonRequest(ctx, "V2DerivativesUpdateLeverageSettingWithOverride", &response, "POST", urlPath, nil, requestPayload)
 	if err != nil {
 		return
 	}
@@ -1218,7 +1785,11 @@ type V2WebsocketsTokenResponse struct {
 // V2WebsocketsToken generates an ephemeral token, which allows user to subscribe to private
 // websocket events. These events include ClientOrderIds (and potentially additional private data)
 func (c *HttpClient) V2WebsocketsToken() (response V2WebsocketsTokenResponse, err error) {
-	err = c.authenticatedFormRequest(&response, "POST", "/v2/websockets_token/", nil, nil)
+	return c.V2WebsocketsTokenWithContext(context.Background())
+}
+
+func (c *HttpClient) V2WebsocketsTokenWithContext(ctx context.Context) (response V2WebsocketsTokenResponse, err error) {
+	err = c.authenticatedFormRequest(ctx, "V2WebsocketsToken", &response, "POST", "/v2/websockets_token/", nil, nil)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV2LimitOrder_LimitPrice(t *testing.T) {
+	var form url.Values
+	echoLimitPrice := true
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		_ = r.ParseForm()
+		form = r.PostForm
+		if echoLimitPrice && form.Has("limit_price") {
+			return 200, `{"id": "1", "price": "50000", "amount": "0.1", "limit_price": "` + form.Get("limit_price") + `"}`
+		}
+		return 200, `{"id": "1", "price": "50000", "amount": "0.1"}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), AutoRoundingEnabled())
+	d := decimal.RequireFromString
+
+	resp, err := c.V2BuyLimitOrder("btcusd", d("50000"), d("0.1"), d("55000.4"), false, false, "", nil, nil, false)
+	assert.NoError(t, err)
+	// the limit price of a buy order is a sell price, so it's rounded up
+	assert.Equal(t, "55001", form.Get("limit_price"))
+	assert.Equal(t, "55001", resp.LimitPrice.String())
+
+	// only what the exchange returns is decoded, so a dropped limit price shows
+	echoLimitPrice = false
+	resp, err = c.V2BuyLimitOrder("btcusd", d("50000"), d("0.1"), d("55000"), false, false, "", nil, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "55000", form.Get("limit_price"))
+	assert.True(t, resp.LimitPrice.IsZero())
+
+	form = nil
+	_, err = c.V2SellLimitOrder("btcusd", d("50000"), d("0.1"), d("55000"), false, false, "", nil, nil, false)
+	assert.ErrorContains(t, err, "must be below price")
+	assert.Nil(t, form)
+
+	_, err = c.V2SellLimitOrder("btcusd", d("50000"), d("0.1"), decimal.Zero, false, false, "", nil, nil, false)
+	assert.NoError(t, err)
+	assert.False(t, form.Has("limit_price"))
+}
+
+func TestV2CancelAllOrders(t *testing.T) {
+	var paths []string
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		if r.Method != http.MethodPost {
+			return 404, `{}` // trading pairs info, loaded when looking up an unknown pair
+		}
+		paths = append(paths, r.URL.Path)
+		return 200, `{"canceled": [{"id": 1, "amount": "0.1", "price": "50000", "type": 0, "currency_pair": "BTC/USD"}], "success": true}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+	resp, err := c.V2CancelAllOrders("all")
+	assert.NoError(t, err)
+	assert.True(t, resp.Success)
+	assert.Len(t, resp.Canceled, 1)
+	assert.Equal(t, int64(1), resp.Canceled[0].Id)
+	assert.Empty(t, resp.Failed)
+
+	_, err = c.V2CancelAllOrders("btcusd")
+	assert.NoError(t, err)
+	_, err = c.V2CancelAllOrders("nosuchpair")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"/v2/cancel_all_orders/", "/v2/cancel_all_orders/btcusd/"}, paths)
+}
+
+func TestV2CancelOrderByClientOrderId(t *testing.T) {
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		_ = r.ParseForm()
+		if r.PostForm.Get("client_order_id") == "known" {
+			return 200, `{"id": 1, "amount": "0.1", "price": "50000", "type": 0}`
+		}
+		return 200, `{"error": "Order not found"}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+	resp, err := c.V2CancelOrderByClientOrderId("known")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), resp.Id)
+
+	_, err = c.V2CancelOrderByClientOrderId("unknown")
+	assert.ErrorIs(t, err, ErrOrderNotFound)
+}
+
+func TestV2StopOrders(t *testing.T) {
+	var forms []url.Values
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		if r.Method != http.MethodPost {
+			return 404, `{}` // trading pairs info, only needed for the best-effort minimum order check
+		}
+		_ = r.ParseForm()
+		forms = append(forms, r.PostForm)
+		return 200, `{"id": "1", "status": "Open"}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+	d := decimal.RequireFromString
+	margin := Isolated
+
+	_, err := c.V2SellStopOrder("btcusd-perp", d("49000"), d("0.1"), TriggerMarkPrice, "sl-1", &margin, nil, true)
+	assert.NoError(t, err)
+	_, err = c.V2BuyStopLimitOrder("btcusd", d("51000"), d("51100"), d("0.1"), "", "", nil, nil, false)
+	assert.NoError(t, err)
+	_, err = c.V2SellTrailingStopOrder("btcusd", d("100"), d("52000"), d("0.1"), TriggerLastPrice, "", nil, nil, false)
+	assert.NoError(t, err)
+	if assert.Len(t, forms, 3) {
+		assert.Equal(t, "49000", forms[0].Get("stop_price"))
+		assert.Equal(t, "mark", forms[0].Get("trigger"))
+		assert.Equal(t, "True", forms[0].Get("reduce_only"))
+		assert.Equal(t, "ISOLATED", forms[0].Get("margin_mode"))
+		assert.Equal(t, "51100", forms[1].Get("price"))
+		assert.Equal(t, "", forms[1].Get("trigger"))
+		assert.Equal(t, "100", forms[2].Get("trailing_delta"))
+		assert.Equal(t, "52000", forms[2].Get("activation_price"))
+	}
+
+	// validated against the pair's decimals before anything is sent
+	_, err = c.V2SellStopOrder("btcusd", d("49000.5"), d("0.1"), "", "", nil, nil, false)
+	assert.ErrorContains(t, err, "stop price 49000.5 has more than 0 decimal places")
+	assert.Len(t, forms, 3)
+}
+
+func TestV2BalanceResponse_UnmarshalJSON(t *testing.T) {
+	var resp V2BalanceResponse
+	err := json.Unmarshal([]byte(`{
+		"btc_balance": "1.5", "btc_available": "1.0", "btc_reserved": "0.5", "btc_withdrawal_fee": "0.0001",
+		"newcoin_balance": "100", "newcoin_available": "100", "newcoin_reserved": "0",
+		"btcusd_fee": "0.4", "newcoinusd_fee": "0.5", "fee": "0.4"
+	}`), &resp)
+	assert.NoError(t, err)
+
+	d := decimal.RequireFromString
+	assert.True(t, d("1.5").Equal(resp.BtcBalance), "fixed fields are still populated")
+	assert.Len(t, resp.Currencies, 2)
+	assert.True(t, d("0.5").Equal(resp.Currencies["btc"].Reserved))
+	assert.True(t, d("0.0001").Equal(resp.Currencies["btc"].WithdrawalFee))
+	assert.True(t, d("100").Equal(resp.Currencies["newcoin"].Available))
+	assert.Len(t, resp.PairFees, 2)
+	assert.True(t, d("0.5").Equal(resp.PairFees["newcoinusd"]))
+}
+
+func TestV2UserTransactionsResponse_Legs(t *testing.T) {
+	var txs []V2UserTransactionsResponse
+	err := json.Unmarshal([]byte(`[
+		{"id": 1, "order_id": 10, "type": "2", "datetime": "2024-01-01 00:00:00", "fee": "0.1", "newcoin": "-20.0", "usd": 50.5, "eur": "0.0", "newcoin_usd": "2.525", "self_trade_order_id": 11},
+		{"id": 2, "type": "0", "datetime": "2024-01-01 00:00:00", "fee": "0", "btc": "0.1", "usd": 0}
+	]`), &txs)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+
+	d := decimal.RequireFromString
+	trade := txs[0]
+	assert.Equal(t, int64(10), trade.OrderId)
+	assert.Equal(t, MarketTradeTransaction, trade.Type)
+	assert.Len(t, trade.Amounts, 3)
+	assert.Equal(t, map[string]decimal.Decimal{"newcoin_usd": d("2.525")}, trade.Rates)
+	base, counter, ok := trade.Legs()
+	assert.True(t, ok)
+	assert.Equal(t, "newcoin", base.Currency)
+	assert.True(t, d("-20").Equal(base.Amount))
+	assert.Equal(t, "usd", counter.Currency)
+	assert.True(t, d("50.5").Equal(counter.Amount))
+	rate, ok := trade.Rate()
+	assert.True(t, ok)
+	assert.True(t, d("2.525").Equal(rate))
+
+	deposit := txs[1]
+	assert.True(t, d("0.1").Equal(deposit.Btc))
+	_, _, ok = deposit.Legs()
+	assert.False(t, ok)
+	_, ok = deposit.Rate()
+	assert.False(t, ok)
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -71,33 +72,49 @@ func (t *TickerResponse) UnmarshalJSON(data []byte) error {
 
 // GET https://www.bitstamp.net/api/ticker/
 func (c *HttpClient) V1Ticker() (response TickerResponse, err error) {
-	err = c.getRequest(&response, "/ticker/", nil)
+	return c.V1TickerWithContext(context.Background())
+}
+
+func (c *HttpClient) V1TickerWithContext(ctx context.Context) (response TickerResponse, err error) {
+	err = c.getRequest(ctx, "V1Ticker", &response, "/ticker/", nil)
 	return
 }
 
 // GET https://www.bitstamp.net/api/ticker_hour/
 func (c *HttpClient) V1HourlyTicker() (response TickerResponse, err error) {
-	err = c.getRequest(&response, "/ticker_hour/", nil)
+	return c.V1HourlyTickerWithContext(context.Background())
+}
+
+func (c *HttpClient) V1HourlyTickerWithContext(ctx context.Context) (response TickerResponse, err error) {
+	err = c.getRequest(ctx, "V1HourlyTicker", &response, "/ticker_hour/", nil)
 	return
 }
 
 // GET https://www.bitstamp.net/api/v2/ticker/{currency_pair}/
 func (c *HttpClient) V2Ticker(currencyPair string) (response TickerResponse, err error) {
-	if err = validateCurrencyPair(currencyPair); err != nil {
+	return c.V2TickerWithContext(context.Background(), currencyPair)
+}
+
+func (c *HttpClient) V2TickerWithContext(ctx context.Context, currencyPair string) (response TickerResponse, err error) {
+	if err = c.validateCurrencyPair(ctx, currencyPair); err != nil {
 		return
 	}
 	urlPath := fmt.Sprintf("/v2/ticker/%s/", currencyPair)
-	err = c.getRequest(&response, urlPath, nil)
+	err = c.getRequest(ctx, "V2Ticker", &response, urlPath, nil)
 	return
 }
 
 // GET https://www.bitstamp.net/api/v2/ticker_hour/{currency_pair}/
 func (c *HttpClient) V2HourlyTicker(currencyPair string) (response TickerResponse, err error) {
-	if err = validateCurrencyPair(currencyPair); err != nil {
+	return c.V2HourlyTickerWithContext(context.Background(), currencyPair)
+}
+
+func (c *HttpClient) V2HourlyTickerWithContext(ctx context.Context, currencyPair string) (response TickerResponse, err error) {
+	if err = c.validateCurrencyPair(ctx, currencyPair); err != nil {
 		return
 	}
 	urlPath := fmt.Sprintf("/v2/ticker_hour/%s/", currencyPair)
-	err = c.getRequest(&response, urlPath, nil)
+	err = c.getRequest(ctx, "V2HourlyTicker", &response, urlPath, nil)
 	return
 }
 
@@ -159,9 +176,13 @@ type V1OrderBookResponse struct {
 
 // GET https://www.bitstamp.net/api/order_book?group=1
 func (c *HttpClient) V1OrderBook(group int) (response V1OrderBookResponse, err error) {
+	return c.V1OrderBookWithContext(context.Background(), group)
+}
+
+func (c *HttpClient) V1OrderBookWithContext(ctx context.Context, group int) (response V1OrderBookResponse, err error) {
 	urlParams := make(url.Values)
 	urlParams.Set("group", strconv.Itoa(group))
-	err = c.getRequest(&response, "/order_book/", &urlParams)
+	err = c.getRequest(ctx, "V1OrderBook", &response, "/order_book/", &urlParams)
 	return
 }
 
@@ -176,7 +197,11 @@ type V2OrderBookResponse struct {
 // - 1 (orders are grouped at same price - default)
 // - 2 (orders with their order ids are not grouped at same price)
 func (c *HttpClient) V2OrderBook(currencyPair string, group int) (response V2OrderBookResponse, err error) {
-	if err = validateCurrencyPair(currencyPair); err != nil {
+	return c.V2OrderBookWithContext(context.Background(), currencyPair, group)
+}
+
+func (c *HttpClient) V2OrderBookWithContext(ctx context.Context, currencyPair string, group int) (response V2OrderBookResponse, err error) {
+	if err = c.validateCurrencyPair(ctx, currencyPair); err != nil {
 		return
 	}
 	switch group {
@@ -184,7 +209,7 @@ func (c *HttpClient) V2OrderBook(currencyPair string, group int) (response V2Ord
 		urlPath := fmt.Sprintf("/v2/order_book/%s/", currencyPair)
 		urlParams := make(url.Values)
 		urlParams.Set("group", strconv.Itoa(group))
-		err = c.getRequest(&response, urlPath, &urlParams)
+		err = c.getRequest(ctx, "V2OrderBook", &response, urlPath, &urlParams)
 	default:
 		err = fmt.Errorf("invalid group parameter value: %d", group)
 	}
@@ -270,7 +295,11 @@ func (tx *V2TransactionsResponse) UnmarshalJSON(bytes []byte) error {
 
 // GET https://www.bitstamp.net/api/v2/transactions/{currency_pair}/?time=day
 func (c *HttpClient) V2Transactions(currencyPair string, timeParam string) (response []V2TransactionsResponse, err error) {
-	if err = validateCurrencyPair(currencyPair); err != nil {
+	return c.V2TransactionsWithContext(context.Background(), currencyPair, timeParam)
+}
+
+func (c *HttpClient) V2TransactionsWithContext(ctx context.Context, currencyPair string, timeParam string) (response []V2TransactionsResponse, err error) {
+	if err = c.validateCurrencyPair(ctx, currencyPair); err != nil {
 		return
 	}
 	urlPath := fmt.Sprintf("/v2/transactions/%s/", currencyPair)
@@ -279,11 +308,11 @@ func (c *HttpClient) V2Transactions(currencyPair string, timeParam string) (resp
 	// The time interval from which we want the transactions to be returned. Possible values are minute, hour (default) or day.
 	switch timeParam {
 	case "":
-		err = c.getRequest(&response, urlPath, nil)
+		err = c.getRequest(ctx, "V2Transactions", &response, urlPath, nil)
 	case "minute", "hour", "day":
 		urlParams := make(url.Values)
 		urlParams.Set("time", timeParam)
-		err = c.getRequest(&response, urlPath, &urlParams)
+		err = c.getRequest(ctx, "V2Transactions", &response, urlPath, &urlParams)
 	default:
 		err = fmt.Errorf("invalid value for time interval: %s", timeParam)
 	}
@@ -295,19 +324,117 @@ func (c *HttpClient) V2Transactions(currencyPair string, timeParam string) (resp
 // Trading pairs info
 //
 
+// StringEnabled is a flag the API represents as "Enabled" or "Disabled".
+type StringEnabled bool
+
+func (se *StringEnabled) UnmarshalJSON(b []byte) error {
+	var item interface{}
+	if err := json.Unmarshal(b, &item); err != nil {
+		return err
+	}
+	switch v := item.(type) {
+	case bool:
+		*se = StringEnabled(v)
+	case string:
+		switch strings.ToLower(v) {
+		case "enabled":
+			*se = true
+		case "disabled", "":
+			*se = false
+		default:
+			return fmt.Errorf("invalid flag: %q", v)
+		}
+	}
+	return nil
+}
+
+func (se StringEnabled) MarshalJSON() ([]byte, error) {
+	if se {
+		return []byte(`"Enabled"`), nil
+	}
+	return []byte(`"Disabled"`), nil
+}
+
+// CurrencyAmount is an amount of a given currency, which the API represents as e.g. "10.00 USD".
+type CurrencyAmount struct {
+	Amount   decimal.Decimal
+	Currency string
+}
+
+func parseCurrencyAmount(s string) (amount CurrencyAmount, err error) {
+	parts := strings.Fields(s)
+	if len(parts) != 2 {
+		err = fmt.Errorf("invalid currency amount: %q", s)
+		return
+	}
+	amount.Amount, err = decimal.NewFromString(parts[0])
+	if err != nil {
+		err = fmt.Errorf("invalid currency amount %q: %v", s, err)
+		return
+	}
+	amount.Currency = parts[1]
+	return
+}
+
+func (ca CurrencyAmount) String() string {
+	return ca.Amount.String() + " " + ca.Currency
+}
+
+func (ca *CurrencyAmount) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*ca = CurrencyAmount{}
+		return nil
+	}
+	amount, err := parseCurrencyAmount(s)
+	if err != nil {
+		return err
+	}
+	*ca = amount
+	return nil
+}
+
+func (ca CurrencyAmount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ca.String())
+}
+
 type V2TradingPairsInfoResponse struct {
-	BaseDecimals           int    `json:"base_decimals"`
-	CounterDecimals        int    `json:"counter_decimals"`
-	Description            string `json:"description"`
-	InstantAndMarketOrders string `json:"instant_and_market_orders"` // TODO: make this a boolean flag
-	MinimumOrder           string `json:"minimum_order"`
-	Name                   string `json:"name"`
-	Trading                string `json:"trading"` // TODO: make this a boolean flag
-	UrlSymbol              string `json:"url_symbol"`
+	BaseDecimals                int             `json:"base_decimals"`
+	CounterDecimals             int             `json:"counter_decimals"`
+	InstantOrderCounterDecimals int             `json:"instant_order_counter_decimals"`
+	Description                 string          `json:"description"`
+	InstantAndMarketOrders      StringEnabled   `json:"instant_and_market_orders"`
+	MinimumOrder                CurrencyAmount  `json:"minimum_order"` // in counter currency
+	Name                        string          `json:"name"`
+	Trading                     StringEnabled   `json:"trading"`
+	UrlSymbol                   string          `json:"url_symbol"`
+	MarketType                  MarketType      `json:"market_type"`
+	TickSize                    decimal.Decimal `json:"tick_size"` // smallest price increment, zero if not returned
+}
+
+// IsPerpetual tells perpetual futures markets apart from spot ones.
+func (info V2TradingPairsInfoResponse) IsPerpetual() bool {
+	return info.MarketType == Perpetual || strings.HasSuffix(info.UrlSymbol, "-perp")
+}
+
+// PriceStep returns the smallest price increment: the tick size if the API returns one, otherwise the one implied
+// by the counter decimals.
+func (info V2TradingPairsInfoResponse) PriceStep() decimal.Decimal {
+	if info.TickSize.IsPositive() {
+		return info.TickSize
+	}
+	return decimal.New(1, -int32(info.CounterDecimals))
 }
 
 func (c *HttpClient) V2TradingPairsInfo() (response []V2TradingPairsInfoResponse, err error) {
-	err = c.getRequest(&response, "/v2/trading-pairs-info/", nil)
+	return c.V2TradingPairsInfoWithContext(context.Background())
+}
+
+func (c *HttpClient) V2TradingPairsInfoWithContext(ctx context.Context) (response []V2TradingPairsInfoResponse, err error) {
+	err = c.getRequest(ctx, "V2TradingPairsInfo", &response, "/v2/trading-pairs-info/", nil)
 	return
 }
 
@@ -378,7 +505,11 @@ type V2OhlcResponse struct {
 //   - step: Timeframe in seconds. Possible options are 60, 180, 300, 900, 1800, 3600, 7200, 14400, 21600, 43200, 86400, 259200
 //   - limit: Limit OHLC results (minimum: 1; maximum: 1000)
 func (c *HttpClient) V2Ohlc(currencyPair string, step, limit int, start, end int64) (response V2OhlcResponse, err error) {
-	if err = validateCurrencyPair(currencyPair); err != nil {
+	return c.V2OhlcWithContext(context.Background(), currencyPair, step, limit, start, end)
+}
+
+func (c *HttpClient) V2OhlcWithContext(ctx context.Context, currencyPair string, step, limit int, start, end int64) (response V2OhlcResponse, err error) {
+	if err = c.validateCurrencyPair(ctx, currencyPair); err != nil {
 		return
 	}
 
@@ -420,7 +551,7 @@ func (c *HttpClient) V2Ohlc(currencyPair string, step, limit int, start, end int
 		}
 	}
 	urlPath := fmt.Sprintf("/v2/ohlc/%s/", currencyPair)
-	err = c.getRequest(&response, urlPath, &args)
+	err = c.getRequest(ctx, "V2Ohlc", &response, urlPath, &args)
 	return
 }
 
@@ -435,25 +566,139 @@ type V2EurUsdResponse struct {
 
 // GET https://www.bitstamp.net/api/v2/eur_usd/
 func (c *HttpClient) V2EurUsd() (response V2EurUsdResponse, err error) {
-	err = c.getRequest(&response, "/v2/eur_usd/", nil)
+	return c.V2EurUsdWithContext(context.Background())
+}
+
+func (c *HttpClient) V2EurUsdWithContext(ctx context.Context) (response V2EurUsdResponse, err error) {
+	err = c.getRequest(ctx, "V2EurUsd", &response, "/v2/eur_usd/", nil)
 	return
 }
 
 // Currencies
 
+type CurrencyType string
+
+const (
+	CryptoCurrency CurrencyType = "crypto"
+	FiatCurrency   CurrencyType = "fiat"
+)
+
 type V2CurrenciesResponse struct {
-	Name            string `json:"name"`
-	Currency        string `json:"currency"`
-	Type            string `json:"type"`
-	Symbol          string `json:"symbol"`
-	Decimals        string `json:"decimal"`
-	Logo            string `json:"logo"`
-	AvailableSupply string `json:"available_supply"`
-	Deposit         string `json:"deposit"`
-	Withdrawal      string `json:"withdrawal"`
+	Name            string          `json:"name"`
+	Currency        string          `json:"currency"` // e.g. "BTC"
+	Type            CurrencyType    `json:"type"`
+	Symbol          string          `json:"symbol"` // e.g. "₿"
+	Decimals        int             `json:"decimals"`
+	Logo            string          `json:"logo"`
+	AvailableSupply decimal.Decimal `json:"available_supply"` // zero if not returned
+	Deposit         StringEnabled   `json:"deposit"`
+	Withdrawal      StringEnabled   `json:"withdrawal"`
+	// chains the currency can be deposited and withdrawn on, e.g. "ethereum" and "polygon" for USDC
+	Networks []V2CurrencyNetwork `json:"networks"`
+}
+
+// UnmarshalJSON tolerates an empty available supply, which is returned for some (e.g. fiat) currencies.
+func (r *V2CurrenciesResponse) UnmarshalJSON(b []byte) error {
+	type fields V2CurrenciesResponse
+	aux := struct {
+		*fields
+		AvailableSupply string `json:"available_supply"`
+	}{fields: (*fields)(r)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	r.AvailableSupply = decimal.Zero
+	if aux.AvailableSupply != "" {
+		supply, err := decimal.NewFromString(aux.AvailableSupply)
+		if err != nil {
+			return fmt.Errorf("invalid available supply %q: %v", aux.AvailableSupply, err)
+		}
+		r.AvailableSupply = supply
+	}
+	return nil
+}
+
+// Network looks up one of the currency's networks by name, case-insensitive.
+func (r V2CurrenciesResponse) Network(network string) (V2CurrencyNetwork, bool) {
+	for _, n := range r.Networks {
+		if strings.EqualFold(n.Network, network) {
+			return n, true
+		}
+	}
+	return V2CurrencyNetwork{}, false
+}
+
+// DepositNetworks returns the names of the networks the currency can currently be deposited on.
+func (r V2CurrenciesResponse) DepositNetworks() []string {
+	var networks []string
+	for _, n := range r.Networks {
+		if n.Deposit {
+			networks = append(networks, n.Network)
+		}
+	}
+	return networks
+}
+
+// WithdrawalNetworks returns the names of the networks the currency can currently be withdrawn on.
+func (r V2CurrenciesResponse) WithdrawalNetworks() []string {
+	var networks []string
+	for _, n := range r.Networks {
+		if n.Withdrawal {
+			networks = append(networks, n.Network)
+		}
+	}
+	return networks
+}
+
+type V2CurrencyNetwork struct {
+	Network                 string          `json:"network"`
+	Deposit                 StringEnabled   `json:"deposit"`
+	Withdrawal              StringEnabled   `json:"withdrawal"`
+	WithdrawalDecimals      int             `json:"withdrawal_decimals"`
+	WithdrawalMinimumAmount decimal.Decimal `json:"withdrawal_minimum_amount"`
+	WithdrawalFee           decimal.Decimal `json:"withdrawal_fee"`
 }
 
 func (c *HttpClient) V2Currencies() (response []V2CurrenciesResponse, err error) {
-	err = c.getRequest(&response, "/v2/currencies/", nil)
+	return c.V2CurrenciesWithContext(context.Background())
+}
+
+func (c *HttpClient) V2CurrenciesWithContext(ctx context.Context) (response []V2CurrenciesResponse, err error) {
+	err = c.getRequest(ctx, "V2Currencies", &response, "/v2/currencies/", nil)
 	return
 }
+
+// CurrencyCatalogue indexes V2Currencies by currency code.
+type CurrencyCatalogue map[string]V2CurrenciesResponse
+
+func NewCurrencyCatalogue(currencies []V2CurrenciesResponse) CurrencyCatalogue {
+	catalogue := make(CurrencyCatalogue, len(currencies))
+	for _, currency := range currencies {
+		catalogue[strings.ToLower(currency.Currency)] = currency
+	}
+	return catalogue
+}
+
+// CurrencyCatalogue loads V2Currencies into a catalogue.
+func (c *HttpClient) CurrencyCatalogue(ctx context.Context) (CurrencyCatalogue, error) {
+	currencies, err := c.V2CurrenciesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading currencies: %w", err)
+	}
+	return NewCurrencyCatalogue(currencies), nil
+}
+
+// Currency looks up a currency by its code (e.g. "btc"), case-insensitive.
+func (cc CurrencyCatalogue) Currency(currency string) (V2CurrenciesResponse, bool) {
+	info, exists := cc[strings.ToLower(currency)]
+	return info, exists
+}
+
+// Network looks up a currency's network, e.g. to find the minimum amount and fee of a withdrawal.
+func (cc CurrencyCatalogue) Network(currency string, network string) (V2CurrencyNetwork, bool) {
+	info, exists := cc.Currency(currency)
+	if !exists {
+		return V2CurrencyNetwork{}, false
+	}
+	return info.Network(network)
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV2TradingPairsInfoResponse_UnmarshalJSON(t *testing.T) {
+	var infos []V2TradingPairsInfoResponse
+	err := json.Unmarshal([]byte(`[
+		{"name": "BTC/USD", "url_symbol": "btcusd", "base_decimals": 8, "counter_decimals": 0, "instant_order_counter_decimals": 2, "minimum_order": "10.00 USD", "trading": "Enabled", "instant_and_market_orders": "Enabled", "description": "Bitcoin / U.S. dollar", "market_type": "SPOT"},
+		{"name": "BTC/USD-PERP", "url_symbol": "btcusd-perp", "base_decimals": 8, "counter_decimals": 0, "minimum_order": "10 USD", "trading": "Disabled", "instant_and_market_orders": "Disabled", "market_type": "PERPETUAL", "tick_size": "5"}
+	]`), &infos)
+	assert.NoError(t, err)
+	assert.Len(t, infos, 2)
+
+	spot, perp := infos[0], infos[1]
+	assert.True(t, bool(spot.Trading))
+	assert.True(t, bool(spot.InstantAndMarketOrders))
+	assert.True(t, decimal.NewFromInt(10).Equal(spot.MinimumOrder.Amount))
+	assert.Equal(t, "USD", spot.MinimumOrder.Currency)
+	assert.Equal(t, 2, spot.InstantOrderCounterDecimals)
+	assert.False(t, spot.IsPerpetual())
+	assert.True(t, decimal.NewFromInt(1).Equal(spot.PriceStep()))
+
+	assert.False(t, bool(perp.Trading))
+	assert.False(t, bool(perp.InstantAndMarketOrders))
+	assert.True(t, perp.IsPerpetual())
+	assert.True(t, decimal.NewFromInt(5).Equal(perp.PriceStep()))
+
+	// round trip
+	b, err := json.Marshal(spot)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"trading":"Enabled"`)
+	assert.Contains(t, string(b), `"minimum_order":"10 USD"`)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"minimum_order": "lots"}`), &V2TradingPairsInfoResponse{}))
+	assert.Error(t, json.Unmarshal([]byte(`{"trading": "Maybe"}`), &V2TradingPairsInfoResponse{}))
+}
+
+func TestCurrencyCatalogue(t *testing.T) {
+	var currencies []V2CurrenciesResponse
+	err := json.Unmarshal([]byte(`[
+		{"name": "USD Coin", "currency": "USDC", "type": "crypto", "symbol": "USDC", "decimals": 6, "available_supply": "32000000000.00", "deposit": "Enabled", "withdrawal": "Enabled", "networks": [
+			{"network": "ethereum", "deposit": "Enabled", "withdrawal": "Enabled", "withdrawal_decimals": 6, "withdrawal_minimum_amount": "10", "withdrawal_fee": "1.5"},
+			{"network": "polygon", "deposit": "Disabled", "withdrawal": "Enabled", "withdrawal_decimals": 6, "withdrawal_minimum_amount": "2", "withdrawal_fee": "0.1"}
+		]},
+		{"name": "Euro", "currency": "EUR", "type": "fiat", "symbol": "€", "decimals": 2, "available_supply": "", "deposit": "Enabled", "withdrawal": "Disabled", "networks": []}
+	]`), &currencies)
+	assert.NoError(t, err)
+
+	catalogue := NewCurrencyCatalogue(currencies)
+	usdc, exists := catalogue.Currency("usdc")
+	assert.True(t, exists)
+	assert.Equal(t, CryptoCurrency, usdc.Type)
+	assert.Equal(t, 6, usdc.Decimals)
+	assert.True(t, decimal.RequireFromString("32000000000").Equal(usdc.AvailableSupply))
+	assert.Equal(t, []string{"ethereum"}, usdc.DepositNetworks())
+	assert.Equal(t, []string{"ethereum", "polygon"}, usdc.WithdrawalNetworks())
+
+	polygon, exists := catalogue.Network("USDC", "Polygon")
+	assert.True(t, exists)
+	assert.True(t, decimal.RequireFromString("2").Equal(polygon.WithdrawalMinimumAmount))
+	assert.True(t, decimal.RequireFromString("0.1").Equal(polygon.WithdrawalFee))
+	_, exists = catalogue.Network("usdc", "solana")
+	assert.False(t, exists)
+
+	eur, exists := catalogue.Currency("EUR")
+	assert.True(t, exists)
+	assert.Equal(t, FiatCurrency, eur.Type)
+	assert.True(t, eur.AvailableSupply.IsZero())
+	assert.True(t, bool(eur.Deposit))
+	assert.False(t, bool(eur.Withdrawal))
+
+	_, exists = catalogue.Currency("doge")
+	assert.False(t, exists)
+}
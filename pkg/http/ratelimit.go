@@ -0,0 +1,195 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitBudgetExhausted is returned by a fail-fast RateLimiter when there is no budget left for a request.
+// It wraps ErrRateLimited, so errors.Is(err, ErrRateLimited) catches both client- and server-side rate limiting.
+var ErrRateLimitBudgetExhausted = fmt.Errorf("client-side request budget exhausted: %w", ErrRateLimited)
+
+// RateLimit allows at most Requests requests in any Interval.
+type RateLimit struct {
+	Requests int
+	Interval time.Duration
+}
+
+type RateLimiterConfig struct {
+	Overall []RateLimit // budgets shared by all requests, on top of the public or private ones
+	Public  []RateLimit // budgets for unauthenticated requests
+	Private []RateLimit // budgets for signed requests
+	// return ErrRateLimitBudgetExhausted instead of waiting for budget to become available
+	FailFast bool
+}
+
+// DefaultRateLimiterConfig follows Bitstamp's documented limits: 400 requests per second and 10000 requests
+// per 10 minutes, for public and signed requests combined.
+func DefaultRateLimiterConfig() RateLimiterConfig {
+	return RateLimiterConfig{
+		Overall: []RateLimit{
+			{Requests: 400, Interval: time.Second},
+			{Requests: 10000, Interval: 10 * time.Minute},
+		},
+	}
+}
+
+// RateLimiter is a client-side token bucket limiter. It is safe for concurrent use and should be shared
+// (see the RateLimiting option) by all HttpClient instances using the same API key.
+type RateLimiter struct {
+	mu       sync.Mutex
+	failFast bool
+	overall  []*tokenBucket
+	public   []*tokenBucket
+	private  []*tokenBucket
+	now      func() time.Time
+}
+
+// NewRateLimiter panics if any of the limits has no requests or no interval.
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	return newRateLimiter(config, time.Now)
+}
+
+func newRateLimiter(config RateLimiterConfig, now func() time.Time) *RateLimiter {
+	l := &RateLimiter{
+		failFast: config.FailFast,
+		now:      now,
+	}
+	start := l.now()
+	buckets := func(limits []RateLimit) []*tokenBucket {
+		result := make([]*tokenBucket, 0, len(limits))
+		for _, limit := range limits {
+			if limit.Requests <= 0 || limit.Interval <= 0 {
+				log.Panicf("invalid rate limit: %d requests per %s", limit.Requests, limit.Interval)
+			}
+			result = append(result, newTokenBucket(limit, start))
+		}
+		return result
+	}
+	l.overall = buckets(config.Overall)
+	l.public = buckets(config.Public)
+	l.private = buckets(config.Private)
+	return l
+}
+
+// RateLimitBudget is the remaining budget of a single RateLimit.
+type RateLimitBudget struct {
+	RateLimit
+	Available float64
+}
+
+type RateLimiterStats struct {
+	Overall []RateLimitBudget
+	Public  []RateLimitBudget
+	Private []RateLimitBudget
+}
+
+// Stats returns the currently available budget.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	budgets := func(buckets []*tokenBucket) []RateLimitBudget {
+		result := make([]RateLimitBudget, 0, len(buckets))
+		for _, b := range buckets {
+			b.refill(now)
+			result = append(result, RateLimitBudget{RateLimit: b.limit, Available: b.tokens})
+		}
+		return result
+	}
+	return RateLimiterStats{
+		Overall: budgets(l.overall),
+		Public:  budgets(l.public),
+		Private: budgets(l.private),
+	}
+}
+
+// Wait takes a single request from the overall budget and the public (signed == false) or private one, blocking
+// until enough budget is available or ctx is done. In fail-fast mode it never blocks.
+func (l *RateLimiter) Wait(ctx context.Context, signed bool) error {
+	buckets := append([]*tokenBucket{}, l.overall...)
+	if signed {
+		buckets = append(buckets, l.private...)
+	} else {
+		buckets = append(buckets, l.public...)
+	}
+
+	for {
+		delay := l.reserve(buckets)
+		if delay == 0 {
+			return nil
+		}
+		if l.failFast {
+			return ErrRateLimitBudgetExhausted
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token from every bucket if all of them have one, otherwise it returns how long
+// to wait before trying again.
+func (l *RateLimiter) reserve(buckets []*tokenBucket) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var delay time.Duration
+	for _, b := range buckets {
+		b.refill(now)
+		if d := b.delay(); d > delay {
+			delay = d
+		}
+	}
+	if delay > 0 {
+		return delay
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	return 0
+}
+
+type tokenBucket struct {
+	limit      RateLimit
+	tokens     float64
+	perSecond  float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		limit:      limit,
+		tokens:     float64(limit.Requests),
+		perSecond:  float64(limit.Requests) / limit.Interval.Seconds(),
+		lastRefill: now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(b.limit.Requests), b.tokens+elapsed*b.perSecond)
+	b.lastRefill = now
+}
+
+// time until a whole token is available
+func (b *tokenBucket) delay() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - b.tokens) / b.perSecond * float64(time.Second)))
+}
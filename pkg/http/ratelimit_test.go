@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_FailFast(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(RateLimiterConfig{
+		Public:   []RateLimit{{Requests: 2, Interval: time.Second}, {Requests: 3, Interval: time.Minute}},
+		Private:  []RateLimit{{Requests: 1, Interval: time.Second}},
+		FailFast: true,
+	}, func() time.Time { return now })
+	ctx := context.Background()
+
+	assert.NoError(t, l.Wait(ctx, false))
+	assert.NoError(t, l.Wait(ctx, false))
+	assert.ErrorIs(t, l.Wait(ctx, false), ErrRateLimitBudgetExhausted)
+	assert.ErrorIs(t, l.Wait(ctx, false), ErrRateLimited)
+
+	// budgets are independent
+	assert.NoError(t, l.Wait(ctx, true))
+	assert.Error(t, l.Wait(ctx, true))
+
+	// per-second budget refilled, per-minute one has a single request left
+	now = now.Add(time.Second)
+	assert.NoError(t, l.Wait(ctx, false))
+	assert.Error(t, l.Wait(ctx, false))
+
+	stats := l.Stats()
+	assert.Equal(t, 1.0, stats.Public[0].Available)
+	assert.InDelta(t, 1.0/20, stats.Public[1].Available, 1e-9)
+	assert.Equal(t, 1.0, stats.Private[0].Available)
+}
+
+func TestRateLimiter_Overall(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(DefaultRateLimiterConfig(), func() time.Time { return now })
+	l.failFast = true
+	ctx := context.Background()
+
+	// public and signed requests share the documented 400 requests per second
+	for i := 0; i < 200; i++ {
+		assert.NoError(t, l.Wait(ctx, false))
+		assert.NoError(t, l.Wait(ctx, true))
+	}
+	assert.ErrorIs(t, l.Wait(ctx, false), ErrRateLimitBudgetExhausted)
+	assert.ErrorIs(t, l.Wait(ctx, true), ErrRateLimitBudgetExhausted)
+	assert.Equal(t, 10000.0-400, l.Stats().Overall[1].Available)
+}
+
+func TestNewRateLimiter_InvalidLimits(t *testing.T) {
+	assert.Panics(t, func() {
+		NewRateLimiter(RateLimiterConfig{Public: []RateLimit{{Requests: 0, Interval: time.Second}}})
+	})
+	assert.Panics(t, func() {
+		NewRateLimiter(RateLimiterConfig{Overall: []RateLimit{{Requests: 10, Interval: 0}}})
+	})
+	assert.Panics(t, func() {
+		NewRateLimiter(RateLimiterConfig{Private: []RateLimit{{Requests: -1, Interval: time.Second}}})
+	})
+}
+
+func TestRateLimiter_Blocking(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{
+		Public: []RateLimit{{Requests: 1, Interval: 50 * time.Millisecond}},
+	})
+	ctx := context.Background()
+
+	start := time.Now()
+	assert.NoError(t, l.Wait(ctx, false))
+	assert.NoError(t, l.Wait(ctx, false))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, l.Wait(ctx, false), context.Canceled)
+}
+
+func TestRateLimiting_SharedBetweenClients(t *testing.T) {
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		return 200, `{"buy": "1", "sell": "1"}`
+	})
+	l := NewRateLimiter(RateLimiterConfig{
+		Public:   []RateLimit{{Requests: 1, Interval: time.Hour}},
+		FailFast: true,
+	})
+	c1 := NewHttpClient(UrlDomain(server.URL), RateLimiting(l))
+	c2 := NewHttpClient(UrlDomain(server.URL), RateLimiting(l))
+
+	_, err := c1.V2EurUsd()
+	assert.NoError(t, err)
+	_, err = c2.V2EurUsd()
+	assert.ErrorIs(t, err, ErrRateLimitBudgetExhausted)
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type OrderReplacement struct {
+	OrderPlacement      // outcome of the new order
+	Atomic         bool // replaced via V2ReplaceOrder, otherwise the original order was canceled before placing the new one
+}
+
+// ReplaceOrder replaces a resting limit order (identified by either orderId or origClOrdId) with order, which must
+// be a limit order for the same pair. It uses V2ReplaceOrder, so there is no window without an order on the book.
+// Where atomic replace isn't available for a market, it falls back to canceling the original order and placing
+// the new one via PlaceOrderRequest. The new order is only placed once the original one is known to be canceled
+// (and not e.g. filled in the meantime), so a lost cancel response can't result in double exposure. If
+// V2ReplaceOrder fails ambiguously (e.g. times out) and the new order can't be found, the outcome is OrderUnknown.
+//
+// A client order id is generated for the new order unless order.ClientOrderId is set. Amounts are not adjusted
+// for partial fills of the original order.
+func (c *HttpClient) ReplaceOrder(ctx context.Context, orderId int64, origClOrdId string, order OrderRequest) (result OrderReplacement) {
+	if order.ClientOrderId == "" {
+		order.ClientOrderId = uuid.NewString()
+	}
+	result.ClientOrderId = order.ClientOrderId
+
+	order = c.roundOrder(ctx, order)
+	if orderId == 0 && origClOrdId == "" {
+		result.Status = OrderRejected
+		result.Err = errors.New("either orderId or origClOrdId is required")
+		return
+	}
+	if order.Type != LimitOrder {
+		result.Status = OrderRejected
+		result.Err = fmt.Errorf("only limit orders can be replaced, got %s", order.Type)
+		return
+	}
+	if err := c.validateOrder(ctx, order); err != nil {
+		result.Status = OrderRejected
+		result.Err = err
+		return
+	}
+
+	result.Atomic = true
+	result.Attempts = 1
+	resp, err := c.V2ReplaceOrderWithContext(ctx, orderId, origClOrdId, order.Price, order.Amount, order.ClientOrderId)
+	if err == nil {
+		result.Status = OrderPlaced
+		result.OrderId = resp.Id
+		return
+	}
+	result.Err = err
+
+	if !isReplaceUnsupported(err) {
+		var apiErr *ApiError
+		if errors.As(err, &apiErr) && !IsTransientError(err) {
+			result.Status = OrderRejected
+			return
+		}
+		if ctx.Err() != nil {
+			result.Status = OrderUnknown
+			return
+		}
+
+		// ambiguous outcome, ask the exchange
+		newOrderId, found, findErr := c.findOrderByClientOrderId(ctx, order.CurrencyPair, order.ClientOrderId)
+		switch {
+		case findErr != nil:
+			result.Status = OrderUnknown
+			result.Err = errors.Join(err, findErr)
+		case found:
+			result.Status = OrderPlaced
+			result.OrderId = newOrderId
+			result.Err = nil
+		default:
+			// the replace might still take effect
+			result.Status = OrderUnknown
+		}
+		return
+	}
+
+	// fall back to cancel-then-place
+	result.Atomic = false
+	result.Attempts = 0
+	if err := c.cancelForReplace(ctx, orderId, origClOrdId); err != nil {
+		result.Status = OrderRejected
+		result.Err = err
+		return
+	}
+	result.OrderPlacement = c.PlaceOrderRequest(ctx, order)
+	return
+}
+
+// isReplaceUnsupported reports whether V2ReplaceOrder failed because atomic replace isn't available (for the
+// market), as opposed to the order itself being rejected.
+func isReplaceUnsupported(err error) bool {
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed {
+		return true
+	}
+	message := strings.ToLower(apiErr.Error())
+	return strings.Contains(message, "not supported") || strings.Contains(message, "not available")
+}
+
+// cancelForReplace cancels the order and returns nil only if the order is known to be canceled.
+func (c *HttpClient) cancelForReplace(ctx context.Context, orderId int64, origClOrdId string) error {
+	var err error
+	if orderId != 0 {
+		_, err = c.V2CancelOrderWithContext(ctx, orderId)
+	} else {
+		_, err = c.V2CancelOrderByClientOrderIdWithContext(ctx, origClOrdId)
+	}
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+
+	// the cancel response might have been lost, or the order isn't open anymore (e.g. filled)
+	status, statusErr := c.V2OrderStatusWithContext(ctx, orderId, origClOrdId, true)
+	if statusErr != nil {
+		return errors.Join(err, statusErr)
+	}
+	if strings.EqualFold(status.Status, "Canceled") || strings.EqualFold(status.Status, "Expired") {
+		return nil
+	}
+	return fmt.Errorf("order not canceled (status %s): %w", status.Status, err)
+}
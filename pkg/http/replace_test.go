@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceOrder(t *testing.T) {
+	type exchange struct {
+		replace     func() (int, string) // response of /v2/replace_order/
+		cancel      func() (int, string) // response of /v2/cancel_order/
+		orderStatus string               // status of the original order
+	}
+	tests := []struct {
+		name     string
+		exchange exchange
+		status   OrderPlacementStatus
+		atomic   bool
+		placed   int // number of orders sent to /v2/buy/btcusd/
+	}{
+		{
+			name: "atomic",
+			exchange: exchange{
+				replace: func() (int, string) { return 200, `{"id": "2", "status": "Open"}` },
+			},
+			status: OrderPlaced,
+			atomic: true,
+		},
+		{
+			name: "rejected",
+			exchange: exchange{
+				replace: func() (int, string) { return 200, `{"status": "error", "reason": "Order not found."}` },
+			},
+			status: OrderRejected,
+			atomic: true,
+		},
+		{
+			name: "fallback",
+			exchange: exchange{
+				replace: func() (int, string) { return 404, `{}` },
+				cancel:  func() (int, string) { return 200, `{"id": 1, "amount": "1", "price": "1", "type": 0}` },
+			},
+			status: OrderPlaced,
+			placed: 1,
+		},
+		{
+			name: "fallback, lost cancel response",
+			exchange: exchange{
+				replace: func() (int, string) {
+					return 200, `{"status": "error", "reason": "Replace order is not supported for this market."}`
+				},
+				cancel:      func() (int, string) { return 502, `Bad Gateway` },
+				orderStatus: "Canceled",
+			},
+			status: OrderPlaced,
+			placed: 1,
+		},
+		{
+			name: "fallback, original order filled",
+			exchange: exchange{
+				replace:     func() (int, string) { return 404, `{}` },
+				cancel:      func() (int, string) { return 200, `{"error": "Order not found"}` },
+				orderStatus: "Finished",
+			},
+			status: OrderRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			placed := 0
+			server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+				mu.Lock()
+				defer mu.Unlock()
+				switch r.URL.Path {
+				case "/v2/replace_order/":
+					return tt.exchange.replace()
+				case "/v2/cancel_order/":
+					return tt.exchange.cancel()
+				case "/v2/order_status/":
+					return 200, `{"id": 1, "status": "` + tt.exchange.orderStatus + `"}`
+				case "/v2/buy/btcusd/":
+					placed++
+					return 200, `{"id": "3", "status": "Open"}`
+				}
+				return 404, `{}`
+			})
+			c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+			order := NewLimitOrder(Buy, "btcusd", decimal.NewFromInt(1), decimal.NewFromInt(100))
+			result := c.ReplaceOrder(context.Background(), 1, "", order)
+			assert.Equal(t, tt.status, result.Status, "%v", result.Err)
+			assert.Equal(t, tt.atomic, result.Atomic)
+			assert.Equal(t, tt.placed, placed)
+			assert.NotEmpty(t, result.ClientOrderId)
+		})
+	}
+
+	t.Run("timed out", func(t *testing.T) {
+		server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+			switch r.URL.Path {
+			case "/v2/replace_order/":
+				time.Sleep(200 * time.Millisecond)
+				return 200, `{"id": "2", "status": "Open"}`
+			case "/v2/order_status/":
+				return 200, `{"status": "error", "reason": "Order not found."}`
+			case "/v2/open_orders/btcusd/":
+				return 200, `[]`
+			}
+			return 404, `{}`
+		})
+		c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"),
+			CustomHttpClient(&http.Client{Timeout: 50 * time.Millisecond}))
+
+		order := NewLimitOrder(Buy, "btcusd", decimal.NewFromInt(1), decimal.NewFromInt(100))
+		result := c.ReplaceOrder(context.Background(), 1, "", order)
+		assert.Equal(t, OrderUnknown, result.Status)
+		assert.True(t, result.Atomic)
+		assert.Error(t, result.Err)
+	})
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HasClientOrderId reports whether the request carries a client_order_id, which makes order placement
+// idempotent: Bitstamp won't accept a second order with the same client_order_id.
+func (r RequestInfo) HasClientOrderId() bool {
+	switch r.ContentType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(r.Payload)
+		return err == nil && values.Get("client_order_id") != ""
+	case "application/json":
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(r.Payload), &payload); err != nil {
+			return false
+		}
+		clOrdId, _ := payload["client_order_id"].(string)
+		return clOrdId != ""
+	}
+	return false
+}
+
+// IsOrderPlacement reports whether the request places a new order, i.e. is POST-ed to /v2/buy/... or /v2/sell/...
+func (r RequestInfo) IsOrderPlacement() bool {
+	return r.Signed && r.Method == http.MethodPost &&
+		(strings.HasPrefix(r.Path, "/v2/buy/") || strings.HasPrefix(r.Path, "/v2/sell/"))
+}
+
+// IsReadOnly reports whether the request merely reads data, i.e. repeating it has no side effects.
+func (r RequestInfo) IsReadOnly() bool {
+	if !r.Signed || r.Method == http.MethodGet {
+		return true
+	}
+	for _, prefix := range readOnlyPrivatePaths {
+		if strings.HasPrefix(r.Path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// signed endpoints which are POST-ed to, but don't change anything
+var readOnlyPrivatePaths = []string{
+	"/v2/balance/",
+	"/v2/account_balances/",
+	"/v2/user_transactions/",
+	"/v2/crypto-transactions/",
+	"/v2/withdrawal-requests/",
+	"/v2/withdrawal/status/",
+	"/v2/liquidation_address/info/",
+	"/v2/fees/",
+	"/v2/open_orders/",
+	"/v2/order_status/",
+	"/v2/websockets_token/",
+}
+
+// RetryPolicy configures retrying of failed requests. Every retry is signed anew, i.e. with a fresh nonce and
+// timestamp. If a retried order placement is refused, the error of the preceding attempt is returned, as the
+// refusal might be due to that attempt having placed the order after all.
+type RetryPolicy struct {
+	MaxAttempts int           // including the first attempt
+	BaseDelay   time.Duration // delay before the first retry, doubled for every following one
+	MaxDelay    time.Duration // upper bound for the delay
+	// Retryable decides whether a request which failed with err should be retried. Defaults to DefaultRetryable.
+	Retryable func(request RequestInfo, err error) bool
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Retryable:   DefaultRetryable,
+	}
+}
+
+// DefaultRetryable retries transient errors (network errors, 5xx responses, rate limiting, nonce errors) of
+// read-only requests and of order placements with a client_order_id. Anything else might have taken effect on the
+// exchange side, so repeating it could e.g. place the same order twice. This includes replacing and canceling by
+// client_order_id: a repeated replace or cancel that already took effect fails with "order not found".
+func DefaultRetryable(request RequestInfo, err error) bool {
+	if !request.IsReadOnly() && !(request.IsOrderPlacement() && request.HasClientOrderId()) {
+		return false
+	}
+	return IsTransientError(err)
+}
+
+// IsTransientError reports whether err is likely to go away when the request is repeated.
+// Transport errors are transient, including timeouts of the *http.Client (its Timeout). The caller's context being
+// done is not: requests then fail with the bare context.Canceled or context.DeadlineExceeded.
+func IsTransientError(err error) bool {
+	if errors.Is(err, ErrRateLimitBudgetExhausted) {
+		return false // client-side fail-fast limiter, waiting is the caller's call
+	}
+
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 ||
+			errors.Is(apiErr, ErrRateLimited) ||
+			errors.Is(apiErr, ErrInvalidNonce)
+	}
+
+	// checked before the context errors, *http.Client timeouts match context.DeadlineExceeded too. the caller's
+	// context being done is reported as the bare context error, which is a net.Error itself.
+	var urlErr *url.Error
+	var netErr net.Error
+	if errors.As(err, &urlErr) || (errors.As(err, &netErr) && netErr.Timeout() && netErr != context.DeadlineExceeded) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (c *HttpClient) withRetries(ctx context.Context, request RequestInfo, do func() error) error {
+	err := do()
+	if c.retryPolicy == nil {
+		return err
+	}
+
+	for attempt := 1; attempt < c.retryPolicy.MaxAttempts; attempt++ {
+		if err == nil || !c.retryPolicy.Retryable(request, err) {
+			return err
+		}
+
+		timer := time.NewTimer(c.retryPolicy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		ambiguous := err
+		err = do()
+		var apiErr *ApiError
+		if request.IsOrderPlacement() && errors.As(err, &apiErr) && !IsTransientError(err) {
+			// the exchange might refuse the resubmission because an earlier one landed after all (e.g. "Order with
+			// this client order id already exists"), so the outcome is still as ambiguous as before
+			return ambiguous
+		}
+	}
+	return err
+}
+
+// exponential backoff with "full jitter"
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay < p.BaseDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay + 1)
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetries(t *testing.T) {
+	var mu sync.Mutex
+	var nonces []string
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		nonces = append(nonces, r.Header.Get("X-Auth-Nonce"))
+		if len(nonces) < 3 {
+			return 503, "Service Unavailable"
+		}
+		return 200, "[]"
+	})
+	reset := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		nonces = nil
+	}
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), Retries(policy))
+
+	t.Run("read-only request is retried with fresh nonces", func(t *testing.T) {
+		reset()
+		_, err := c.V2OpenOrders("all")
+		assert.NoError(t, err)
+		assert.Len(t, nonces, 3)
+		assert.NotEqual(t, nonces[0], nonces[1])
+		assert.NotEqual(t, nonces[1], nonces[2])
+	})
+
+	t.Run("order without client order id is not retried", func(t *testing.T) {
+		reset()
+		_, err := c.V2BuyLimitOrder("btcusd", decimal.NewFromInt(1), decimal.NewFromInt(1), decimal.Zero, false, false, "", nil, nil, false)
+		assert.ErrorIs(t, err, ErrMaintenance)
+		assert.Len(t, nonces, 1)
+	})
+
+	t.Run("order with client order id is retried", func(t *testing.T) {
+		reset()
+		_, err := c.V2BuyLimitOrder("btcusd", decimal.NewFromInt(1), decimal.NewFromInt(1), decimal.Zero, false, false, "my-order-1", nil, nil, false)
+		// "[]" is not a valid order response, but we only care about it being retried
+		assert.NotErrorIs(t, err, ErrMaintenance)
+		assert.Len(t, nonces, 3)
+	})
+
+	t.Run("replace with client order id is not retried", func(t *testing.T) {
+		reset()
+		_, err := c.V2ReplaceOrder(1, "", decimal.NewFromInt(1), decimal.NewFromInt(1), "my-order-2")
+		assert.ErrorIs(t, err, ErrMaintenance)
+		assert.Len(t, nonces, 1)
+	})
+
+	t.Run("cancel by client order id is not retried", func(t *testing.T) {
+		reset()
+		_, err := c.V2CancelOrderByClientOrderId("my-order-1")
+		assert.ErrorIs(t, err, ErrMaintenance)
+		assert.Len(t, nonces, 1)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		reset()
+		c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), Retries(RetryPolicy{MaxAttempts: 2}))
+		_, err := c.V2AccountBalances()
+		assert.ErrorIs(t, err, ErrMaintenance)
+		assert.Len(t, nonces, 2)
+	})
+}
+
+func TestRetries_ResubmissionRefused(t *testing.T) {
+	requests := 0
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		requests++
+		if requests == 1 {
+			return 502, "Bad Gateway" // the order landed nevertheless
+		}
+		return 200, `{"status": "error", "reason": {"__all__": ["Order with this client order id already exists."]}}`
+	})
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), Retries(policy))
+
+	_, err := c.V2BuyLimitOrder("btcusd", decimal.NewFromInt(1), decimal.NewFromInt(1), decimal.Zero, false, false, "my-order-1", nil, nil, false)
+	var apiErr *ApiError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, 502, apiErr.StatusCode)
+	}
+	assert.Equal(t, 2, requests)
+}
+
+func TestRetries_ClientTimeout(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		mu.Lock()
+		requests++
+		slow := requests == 1
+		mu.Unlock()
+		if slow {
+			time.Sleep(200 * time.Millisecond)
+		}
+		return 200, "[]"
+	})
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), Retries(policy),
+		CustomHttpClient(&http.Client{Timeout: 50 * time.Millisecond}))
+
+	_, err := c.V2OpenOrders("all")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestIsTransientError_Context(t *testing.T) {
+	assert.False(t, IsTransientError(context.Canceled))
+	assert.False(t, IsTransientError(context.DeadlineExceeded))
+	assert.False(t, IsTransientError(fmt.Errorf("wrapped: %w", context.DeadlineExceeded)))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for attempt := 1; attempt < 70; attempt++ {
+		delay := p.backoff(attempt)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, p.MaxDelay)
+	}
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// RoundingPolicy decides how AutoRoundingEnabled (or the Rounding option) rounds order prices to the pair's tick
+// size. Amounts are always rounded down, so an order never asks for more than the caller has.
+type RoundingPolicy string
+
+const (
+	// StingyRounding rounds buy prices down and sell prices up, i.e. never pays more (or sells for less) than
+	// asked and never crosses the spread because of rounding. The default.
+	StingyRounding RoundingPolicy = "STINGY"
+	// AggressiveRounding rounds buy prices up and sell prices down, i.e. rather pays a tick more than misses a fill.
+	AggressiveRounding RoundingPolicy = "AGGRESSIVE"
+)
+
+// RoundPrice rounds price to a multiple of step (e.g. the pair's TradingPair.PriceStep) in the policy's
+// direction for the given side.
+func (p RoundingPolicy) RoundPrice(side OrderSide, price, step decimal.Decimal) decimal.Decimal {
+	if !step.IsPositive() {
+		return price
+	}
+	up := side == Sell
+	if p == AggressiveRounding {
+		up = !up
+	}
+	steps := price.Div(step)
+	if up {
+		steps = steps.Ceil()
+	} else {
+		steps = steps.Floor()
+	}
+	return steps.Mul(step)
+}
+
+// RoundAmount rounds amount down to the given number of decimal places.
+func (p RoundingPolicy) RoundAmount(amount decimal.Decimal, decimals int32) decimal.Decimal {
+	return amount.RoundFloor(decimals)
+}
+
+// round rounds all amounts and prices of the order to the pair's decimals and tick size.
+func (p RoundingPolicy) round(order OrderRequest, pair TradingPair) OrderRequest {
+	if order.Type == InstantOrder && order.Side == Buy {
+		order.Amount = p.RoundAmount(order.Amount, pair.InstantOrderCounterDecimals)
+	} else {
+		order.Amount = p.RoundAmount(order.Amount, pair.BaseDecimals)
+	}
+
+	step := pair.PriceStep
+	if !step.IsPositive() {
+		step = decimal.New(1, -pair.CounterDecimals)
+	}
+	opposite := Buy
+	if order.Side == Buy {
+		opposite = Sell
+	}
+	order.Price = p.RoundPrice(order.Side, order.Price, step)
+	order.StopPrice = p.RoundPrice(order.Side, order.StopPrice, step)
+	order.ActivationPrice = p.RoundPrice(order.Side, order.ActivationPrice, step)
+	// the limit price is the price of the opposite order placed once this one executes
+	order.LimitPrice = p.RoundPrice(opposite, order.LimitPrice, step)
+	// not side-dependent, rounded down like amounts
+	order.TrailingDelta = order.TrailingDelta.Div(step).Floor().Mul(step)
+	return order
+}
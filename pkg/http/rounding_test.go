@@ -0,0 +1,93 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundingPolicy_RoundPrice(t *testing.T) {
+	d := decimal.RequireFromString
+	cases := []struct {
+		policy RoundingPolicy
+		side   OrderSide
+		price  string
+		step   string
+		want   string
+	}{
+		{StingyRounding, Buy, "100.57", "0.1", "100.5"},
+		{StingyRounding, Sell, "100.51", "0.1", "100.6"},
+		{AggressiveRounding, Buy, "100.51", "0.1", "100.6"},
+		{AggressiveRounding, Sell, "100.57", "0.1", "100.5"},
+		{StingyRounding, Buy, "50004", "5", "50000"},
+		{StingyRounding, Sell, "50001", "5", "50005"},
+		{StingyRounding, Sell, "50005", "5", "50005"},
+		{StingyRounding, Buy, "0", "5", "0"},
+	}
+	for _, tc := range cases {
+		got := tc.policy.RoundPrice(tc.side, d(tc.price), d(tc.step))
+		assert.Equal(t, d(tc.want).String(), got.String(), "%s %s %s @ %s", tc.policy, tc.side, tc.price, tc.step)
+	}
+
+	assert.Equal(t, "0.12345678", StingyRounding.RoundAmount(d("0.123456789"), 8).String())
+	assert.Equal(t, "0.12345678", AggressiveRounding.RoundAmount(d("0.123456789"), 8).String())
+}
+
+func TestAutoRounding(t *testing.T) {
+	var forms []url.Values
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		if r.URL.Path == "/v2/trading-pairs-info/" {
+			return 200, `[{"url_symbol": "btcusd", "base_decimals": 8, "counter_decimals": 0, "instant_order_counter_decimals": 2, "minimum_order": "10 USD", "trading": "Enabled", "instant_and_market_orders": "Enabled", "tick_size": "5"}]`
+		}
+		_ = r.ParseForm()
+		forms = append(forms, r.PostForm)
+		return 200, `{"id": "1"}`
+	})
+	d := decimal.RequireFromString
+	ctx := context.Background()
+
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), AutoRoundingEnabled())
+	_, err := c.V2SellLimitOrder("btcusd", d("50001.7"), d("0.123456789"), decimal.Zero, false, false, "", nil, nil, false)
+	assert.NoError(t, err)
+	_, err = c.V2BuyMarketOrder("btcusd", d("0.123456789"), "", nil, nil, false)
+	assert.NoError(t, err)
+	_, err = c.V2BuyInstantOrder("btcusd", d("10.509"), "", nil, nil, false)
+	assert.NoError(t, err)
+	_, err = c.SubmitOrder(ctx, NewStopOrder(Sell, "btcusd", d("49999"), d("0.1")))
+	assert.NoError(t, err)
+
+	aggressive := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), Rounding(AggressiveRounding))
+	_, err = aggressive.V2BuyLimitOrder("btcusd", d("50001.7"), d("0.1"), decimal.Zero, false, false, "", nil, nil, false)
+	assert.NoError(t, err)
+
+	if assert.Len(t, forms, 5) {
+		assert.Equal(t, "50005", forms[0].Get("price"))
+		assert.Equal(t, "0.12345678", forms[0].Get("amount"))
+		assert.Equal(t, "0.12345678", forms[1].Get("amount"))
+		assert.Equal(t, "10.5", forms[2].Get("amount"), "instant buy amounts have the instant order counter decimals")
+		assert.Equal(t, "50000", forms[3].Get("stop_price"))
+		assert.Equal(t, "50005", forms[4].Get("price"))
+	}
+}
+
+func TestAutoRounding_InstantBuyWithEmbeddedPairs(t *testing.T) {
+	var form url.Values
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		if r.Method != http.MethodPost {
+			return 404, `{}` // trading pairs info unavailable, the embedded table is used
+		}
+		_ = r.ParseForm()
+		form = r.PostForm
+		return 200, `{"id": "1"}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), AutoRoundingEnabled())
+
+	// btcusd prices have no decimals, but instant buy amounts are in dollars and cents
+	_, err := c.V2BuyInstantOrder("btcusd", decimal.RequireFromString("10.50"), "", nil, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.5", form.Get("amount"))
+}
@@ -5,6 +5,7 @@ type rounding struct {
 	Counter int32
 }
 
+// embedded fallback for PairRegistry, used until trading pairs info has been loaded from the exchange.
 // to generate:
 // curl -s https://www.bitstamp.net/api/v2/trading-pairs-info/ | jq -r '.[] | "\"\(.url_symbol)\": {\(.base_decimals), \(.counter_decimals)},"' | sort
 // curl -s https://perps-test.bitstamp.net/api/v2/trading-pairs-info/ | jq -r '.[] | "\"\(.url_symbol)\": {\(.base_decimals), \(.counter_decimals)},"' | sort
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"context"
+)
+
+// UserTransactionsIterator walks the complete user transaction history, oldest transactions first, one page at a
+// time. Bitstamp doesn't accept offsets beyond 200000, so once that ceiling is reached the iterator continues with
+// since_id instead. Use it like bufio.Scanner:
+//
+//	it := c.UserTransactionsIterator("all", nil, nil, nil)
+//	for it.Next(ctx) {
+//		tx := it.Transaction()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type UserTransactionsIterator struct {
+	client            *HttpClient
+	currencyPairOrAll string
+	sinceTimestamp    *int64
+	untilTimestamp    *int64
+	pageSize          int64
+	maxOffset         int64
+
+	offset  int64
+	sinceId *int64 // once set, pages are requested by since_id instead of offset
+	lastId  int64  // id of the last transaction returned, to skip duplicates when switching to since_id
+	page    []V2UserTransactionsResponse
+	current V2UserTransactionsResponse
+	done    bool
+	err     error
+}
+
+// UserTransactionsIterator returns an iterator over the transactions of the given pair (or "all"), optionally
+// limited to a time range (unix seconds) and to transactions starting at sinceId.
+func (c *HttpClient) UserTransactionsIterator(currencyPairOrAll string, sinceTimestamp *int64, untilTimestamp *int64, sinceId *int64) *UserTransactionsIterator {
+	return &UserTransactionsIterator{
+		client:            c,
+		currencyPairOrAll: currencyPairOrAll,
+		sinceTimestamp:    sinceTimestamp,
+		untilTimestamp:    untilTimestamp,
+		pageSize:          maxUserTransactionsLimit,
+		maxOffset:         maxUserTransactionsOffset,
+		sinceId:           sinceId,
+	}
+}
+
+// Next advances to the next transaction, fetching another page if needed. It returns false once the history is
+// exhausted, a request failed or ctx is done; Err tells these apart.
+func (it *UserTransactionsIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		it.fetch(ctx)
+	}
+
+	it.current, it.page = it.page[0], it.page[1:]
+	it.lastId = it.current.Id
+	return true
+}
+
+// Transaction returns the transaction Next advanced to.
+func (it *UserTransactionsIterator) Transaction() V2UserTransactionsResponse {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, nil if the whole history has been walked.
+func (it *UserTransactionsIterator) Err() error {
+	return it.err
+}
+
+func (it *UserTransactionsIterator) fetch(ctx context.Context) {
+	sort := Ascending
+	var offset *int64
+	if it.sinceId == nil {
+		offset = &it.offset
+	}
+
+	page, err := it.client.V2UserTransactionsPageWithContext(ctx, it.currencyPairOrAll, offset, &it.pageSize, &sort, it.sinceTimestamp, it.untilTimestamp, it.sinceId)
+	if err != nil {
+		it.err = contextError(ctx, err)
+		return
+	}
+	if int64(len(page)) < it.pageSize {
+		it.done = true
+	}
+
+	// skip anything already returned, in case pages overlap (e.g. when switching from offset to since_id)
+	fresh := page[:0]
+	for _, tx := range page {
+		if it.lastId == 0 || tx.Id > it.lastId {
+			fresh = append(fresh, tx)
+		}
+	}
+	if len(fresh) == 0 {
+		// a full page without anything new, don't loop forever
+		it.done = true
+	}
+	it.page = fresh
+
+	if it.sinceId == nil {
+		it.offset += int64(len(page))
+		if it.offset <= it.maxOffset {
+			return
+		}
+	}
+	if len(fresh) > 0 {
+		nextId := fresh[len(fresh)-1].Id + 1
+		it.sinceId = &nextId
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserTransactionsIterator(t *testing.T) {
+	const total = 25
+	var requests []string
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		_ = r.ParseForm()
+		requests = append(requests, r.PostForm.Encode())
+		limit, _ := strconv.Atoi(r.PostForm.Get("limit"))
+		offset, _ := strconv.Atoi(r.PostForm.Get("offset"))
+		first := offset + 1
+		if sinceId := r.PostForm.Get("since_id"); sinceId != "" {
+			first, _ = strconv.Atoi(sinceId)
+		}
+
+		page := []V2UserTransactionsResponse{}
+		for id := first; id <= total && len(page) < limit; id++ {
+			page = append(page, V2UserTransactionsResponse{Id: int64(id), Type: MarketTradeTransaction})
+		}
+		body, _ := json.Marshal(page)
+		return 200, string(body)
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+	it := c.UserTransactionsIterator("all", nil, nil, nil)
+	it.pageSize, it.maxOffset = 10, 10
+
+	var ids []int64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Transaction().Id)
+	}
+	assert.NoError(t, it.Err())
+	assert.Len(t, ids, total)
+	for i, id := range ids {
+		assert.Equal(t, int64(i+1), id)
+	}
+	assert.Equal(t, []string{
+		"limit=10&offset=0&sort=asc",
+		"limit=10&offset=10&sort=asc",
+		"limit=10&offset=0&since_id=21&sort=asc",
+	}, requests)
+
+	// the plain endpoint keeps returning the latest 1000 transactions
+	requests = nil
+	txs, err := c.V2UserTransactions("all")
+	assert.NoError(t, err)
+	assert.Len(t, txs, total)
+	assert.Equal(t, []string{"limit=1000&offset=0&sort=desc"}, requests)
+}
+
+func TestUserTransactionsIterator_ContextCancellation(t *testing.T) {
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		return 200, `[{"id": 1}, {"id": 2}]`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	it := c.UserTransactionsIterator("btcusd", nil, nil, nil)
+	it.pageSize = 2
+	assert.True(t, it.Next(ctx))
+	assert.True(t, it.Next(ctx))
+	cancel()
+	assert.False(t, it.Next(ctx))
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+//
+// Transfers between the main account and its sub-accounts
+//
+
+type V2TransferResponse struct {
+	Status string `json:"status"`
+}
+
+func transferParams(currency string, amount decimal.Decimal) (map[string]string, error) {
+	if currency == "" {
+		return nil, errors.New("currency is required")
+	}
+	if !amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive: %s", amount)
+	}
+	return map[string]string{
+		"currency": strings.ToLower(currency),
+		"amount":   amount.String(),
+	}, nil
+}
+
+// POST https://www.bitstamp.net/api/v2/transfer-to-main/
+//
+// Moves funds from a sub-account to the main account. Called with a sub-account's credentials, subAccountId is
+// left nil. Called with the main account's, it names the sub-account to transfer from, defaulting to the one set
+// with OnBehalfOfSubAccount.
+func (c *HttpClient) V2TransferToMain(currency string, amount decimal.Decimal, subAccountId *int64) (response V2TransferResponse, err error) {
+	return c.V2TransferToMainWithContext(context.Background(), currency, amount, subAccountId)
+}
+
+func (c *HttpClient) V2TransferToMainWithContext(ctx context.Context, currency string, amount decimal.Decimal, subAccountId *int64) (response V2TransferResponse, err error) {
+	params, err := transferParams(currency, amount)
+	if err != nil {
+		return
+	}
+	if subAccountId == nil {
+		subAccountId = c.subAccount
+	}
+	if subAccountId != nil {
+		params["subAccount"] = strconv.FormatInt(*subAccountId, 10)
+	}
+
+	err = c.authenticatedFormRequest(ctx, "V2TransferToMain", &response, "POST", "/v2/transfer-to-main/", nil, params)
+	if err != nil {
+		err = fmt.Errorf("error transferring %s %s to main account: %w", amount, currency, err)
+	}
+	return
+}
+
+// POST https://www.bitstamp.net/api/v2/transfer-from-main/
+//
+// Moves funds from the main account to the given sub-account. Requires the main account's credentials.
+func (c *HttpClient) V2TransferFromMain(currency string, amount decimal.Decimal, subAccountId int64) (response V2TransferResponse, err error) {
+	return c.V2TransferFromMainWithContext(context.Background(), currency, amount, subAccountId)
+}
+
+func (c *HttpClient) V2TransferFromMainWithContext(ctx context.Context, currency string, amount decimal.Decimal, subAccountId int64) (response V2TransferResponse, err error) {
+	params, err := transferParams(currency, amount)
+	if err != nil {
+		return
+	}
+	params["subAccount"] = strconv.FormatInt(subAccountId, 10)
+
+	err = c.authenticatedFormRequest(ctx, "V2TransferFromMain", &response, "POST", "/v2/transfer-from-main/", nil, params)
+	if err != nil {
+		err = fmt.Errorf("error transferring %s %s to sub-account %d: %w", amount, currency, subAccountId, err)
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestV2Transfers(t *testing.T) {
+	var paths []string
+	var forms []url.Values
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		_ = r.ParseForm()
+		paths = append(paths, r.URL.Path)
+		forms = append(forms, r.PostForm)
+		if r.PostForm.Get("currency") == "xyz" {
+			return 200, `{"status": "error", "reason": "Invalid currency."}`
+		}
+		return 200, `{"status": "ok"}`
+	})
+	amount := decimal.RequireFromString("0.12345678")
+
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+	_, err := c.V2TransferToMain("BTC", amount, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, url.Values{"currency": {"btc"}, "amount": {"0.12345678"}}, forms[0])
+
+	resp, err := c.V2TransferFromMain("usd", decimal.NewFromInt(1000), 123)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, "123", forms[1].Get("subAccount"))
+
+	_, err = c.V2TransferFromMain("xyz", amount, 123)
+	assert.ErrorContains(t, err, "Invalid currency")
+
+	// the client's sub-account is used unless another one is given
+	c = NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"), OnBehalfOfSubAccount(456))
+	_, err = c.V2TransferToMain("btc", amount, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "456", forms[3].Get("subAccount"))
+	other := int64(789)
+	_, err = c.V2TransferToMain("btc", amount, &other)
+	assert.NoError(t, err)
+	assert.Equal(t, "789", forms[4].Get("subAccount"))
+
+	_, err = c.V2TransferToMain("btc", decimal.Zero, nil)
+	assert.ErrorContains(t, err, "amount must be positive")
+	assert.Equal(t, []string{
+		"/v2/transfer-to-main/", "/v2/transfer-from-main/", "/v2/transfer-from-main/",
+		"/v2/transfer-to-main/", "/v2/transfer-to-main/",
+	}, paths)
+}
@@ -0,0 +1,380 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrWithdrawalFailed is returned by WaitForWithdrawal for canceled and failed withdrawals.
+var ErrWithdrawalFailed = errors.New("withdrawal failed")
+
+type WithdrawalStatus int
+
+const (
+	WithdrawalOpen      WithdrawalStatus = 0
+	WithdrawalInProcess WithdrawalStatus = 1
+	WithdrawalFinished  WithdrawalStatus = 2
+	WithdrawalCanceled  WithdrawalStatus = 3
+	WithdrawalFailed    WithdrawalStatus = 4
+)
+
+var withdrawalStatusNames = map[WithdrawalStatus]string{
+	WithdrawalOpen:      "Open",
+	WithdrawalInProcess: "In process",
+	WithdrawalFinished:  "Finished",
+	WithdrawalCanceled:  "Canceled",
+	WithdrawalFailed:    "Failed",
+}
+
+func (ws WithdrawalStatus) String() string {
+	if name, exists := withdrawalStatusNames[ws]; exists {
+		return name
+	}
+	return strconv.Itoa(int(ws))
+}
+
+// Done reports whether the withdrawal won't change anymore.
+func (ws WithdrawalStatus) Done() bool {
+	return ws == WithdrawalFinished || ws == WithdrawalCanceled || ws == WithdrawalFailed
+}
+
+// UnmarshalJSON accepts status codes (as numbers or strings) as well as status names.
+func (ws *WithdrawalStatus) UnmarshalJSON(b []byte) error {
+	var item interface{}
+	if err := json.Unmarshal(b, &item); err != nil {
+		return err
+	}
+	switch v := item.(type) {
+	case float64:
+		*ws = WithdrawalStatus(v)
+	case string:
+		if code, err := strconv.Atoi(v); err == nil {
+			*ws = WithdrawalStatus(code)
+			return nil
+		}
+		for status, name := range withdrawalStatusNames {
+			if strings.EqualFold(v, name) {
+				*ws = status
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid withdrawal status: %q", v)
+	}
+	return nil
+}
+
+//
+// Crypto withdrawals
+//
+
+// currencies whose withdrawals take an extra recipient identifier besides the address
+var (
+	destinationTagCurrencies = map[string]bool{"xrp": true}
+	memoIdCurrencies         = map[string]bool{"xlm": true, "hbar": true}
+)
+
+// CryptoWithdrawalRequest describes a withdrawal to a crypto address, see V2CryptoWithdrawal.
+type CryptoWithdrawalRequest struct {
+	Currency string // e.g. "btc"
+	Amount   decimal.Decimal
+	Address  string
+	Network  string // optional for currencies available on a single network, e.g. "ethereum" or "polygon" for usdc
+
+	DestinationTag *uint32 // XRP only, optional
+	MemoId         string  // XLM and HBAR only, optional
+
+	// travel rule information, required for withdrawals to other VASPs in some jurisdictions
+	ContactThirdparty bool   // the recipient is not the account holder
+	ContactUuid       string // contact (recipient) as registered in Bitstamp's address book
+	VaspUuid          string // receiving VASP, empty for self-hosted wallets
+}
+
+// Validate checks the request for mistakes that can be caught without asking the exchange.
+func (r CryptoWithdrawalRequest) Validate() error {
+	currency := strings.ToLower(r.Currency)
+	if currency == "" {
+		return errors.New("currency is required")
+	}
+	if !r.Amount.IsPositive() {
+		return fmt.Errorf("amount must be positive: %s", r.Amount)
+	}
+	if r.Address == "" {
+		return errors.New("address is required")
+	}
+	if err := ValidateAddress(currency, r.Network, r.Address); err != nil {
+		return err
+	}
+	if r.DestinationTag != nil && !destinationTagCurrencies[currency] {
+		return fmt.Errorf("destination tag is not supported for %s withdrawals", currency)
+	}
+	if r.MemoId != "" && !memoIdCurrencies[currency] {
+		return fmt.Errorf("memo id is not supported for %s withdrawals", currency)
+	}
+	if r.ContactThirdparty && r.ContactUuid == "" {
+		return errors.New("contact uuid is required for third party withdrawals")
+	}
+	return nil
+}
+
+func (r CryptoWithdrawalRequest) params() map[string]string {
+	params := map[string]string{
+		"amount":  r.Amount.String(),
+		"address": r.Address,
+	}
+	if r.Network != "" {
+		params["network"] = r.Network
+	}
+	if r.DestinationTag != nil {
+		params["destination_tag"] = strconv.FormatUint(uint64(*r.DestinationTag), 10)
+	}
+	if r.MemoId != "" {
+		params["memo_id"] = r.MemoId
+	}
+	if r.ContactThirdparty {
+		params["contact_thirdparty"] = "True"
+	}
+	if r.ContactUuid != "" {
+		params["contact_uuid"] = r.ContactUuid
+	}
+	if r.VaspUuid != "" {
+		params["vasp_uuid"] = r.VaspUuid
+	}
+	return params
+}
+
+type V2CryptoWithdrawalResponse struct {
+	Id StringInt `json:"id"`
+}
+
+// POST https://www.bitstamp.net/api/v2/{currency}_withdrawal/
+//
+// Withdrawals are never retried (see Retries), a failed request might have been executed nevertheless. Check
+// V2WithdrawalRequests before trying again.
+func (c *HttpClient) V2CryptoWithdrawal(request CryptoWithdrawalRequest) (response V2CryptoWithdrawalResponse, err error) {
+	return c.V2CryptoWithdrawalWithContext(context.Background(), request)
+}
+
+func (c *HttpClient) V2CryptoWithdrawalWithContext(ctx context.Context, request CryptoWithdrawalRequest) (response V2CryptoWithdrawalResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return
+	}
+	urlPath := fmt.Sprintf("/v2/%s_withdrawal/", strings.ToLower(request.Currency))
+
+	err = c.authenticatedFormRequest(ctx, "V2CryptoWithdrawal", &response, "POST", urlPath, nil, request.params())
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) && len(apiErr.FieldReasons["address"]) > 0 && !errors.Is(err, ErrInvalidAddress) {
+		// whatever the exchange has to say about the address, it didn't accept it
+		err = fmt.Errorf("%w: %w", ErrInvalidAddress, err)
+	}
+	if err != nil {
+		err = fmt.Errorf("error withdrawing %s %s: %w", request.Amount, request.Currency, err)
+	}
+	return
+}
+
+// WaitForWithdrawal polls V2WithdrawalRequests every interval until the withdrawal is done, i.e. finished,
+// canceled or failed. The latter two result in ErrWithdrawalFailed. Transient errors are polled through, the
+// withdrawal not (yet) showing up in the list as well.
+func (c *HttpClient) WaitForWithdrawal(ctx context.Context, withdrawalId int64, interval time.Duration) (withdrawal V2WithdrawalRequestsResponse, err error) {
+	if interval <= 0 {
+		return withdrawal, fmt.Errorf("invalid polling interval: %s", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var withdrawals []V2WithdrawalRequestsResponse
+		withdrawals, err = c.V2WithdrawalRequestsWithContext(ctx, withdrawalId, "")
+		if err != nil && !IsTransientError(err) {
+			return withdrawal, contextError(ctx, err)
+		}
+		for _, w := range withdrawals {
+			if int64(w.Id) != withdrawalId {
+				continue
+			}
+			withdrawal = w
+			switch w.Status {
+			case WithdrawalFinished:
+				return withdrawal, nil
+			case WithdrawalCanceled, WithdrawalFailed:
+				return withdrawal, fmt.Errorf("withdrawal %d %s: %w", withdrawalId, strings.ToLower(w.Status.String()), ErrWithdrawalFailed)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return withdrawal, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+//
+// Fiat (bank) withdrawals
+//
+
+type BankWithdrawalType string
+
+const (
+	SepaWithdrawal          BankWithdrawalType = "sepa"
+	InternationalWithdrawal BankWithdrawalType = "international" // wire transfer
+)
+
+// BankWithdrawalBeneficiary is the holder of the account the money is sent to.
+type BankWithdrawalBeneficiary struct {
+	Name       string
+	Iban       string
+	Bic        string
+	Address    string
+	PostalCode string
+	City       string
+	Country    string
+}
+
+// BankWithdrawalBank is the beneficiary's bank, needed for international withdrawals only.
+type BankWithdrawalBank struct {
+	Name       string
+	Address    string
+	PostalCode string
+	City       string
+	Country    string
+}
+
+// BankWithdrawalRequest describes a withdrawal to a bank account, see V2OpenBankWithdrawal.
+type BankWithdrawalRequest struct {
+	Type            BankWithdrawalType
+	Amount          decimal.Decimal
+	AccountCurrency string // currency withdrawn from the account, e.g. "EUR"
+	Currency        string // currency the beneficiary receives, international withdrawals only
+	Beneficiary     BankWithdrawalBeneficiary
+	Bank            BankWithdrawalBank // international withdrawals only
+	Comment         string             // optional
+}
+
+// Validate checks the request for missing fields, the exchange is left to check the values.
+func (r BankWithdrawalRequest) Validate() error {
+	if r.Type != SepaWithdrawal && r.Type != InternationalWithdrawal {
+		return fmt.Errorf("invalid bank withdrawal type: %q", r.Type)
+	}
+	if !r.Amount.IsPositive() {
+		return fmt.Errorf("amount must be positive: %s", r.Amount)
+	}
+	required := []struct{ name, value string }{
+		{"account currency", r.AccountCurrency},
+		{"beneficiary name", r.Beneficiary.Name},
+		{"beneficiary iban", r.Beneficiary.Iban},
+		{"beneficiary bic", r.Beneficiary.Bic},
+		{"beneficiary address", r.Beneficiary.Address},
+		{"beneficiary postal code", r.Beneficiary.PostalCode},
+		{"beneficiary city", r.Beneficiary.City},
+		{"beneficiary country", r.Beneficiary.Country},
+	}
+	if r.Type == InternationalWithdrawal {
+		required = append(required, []struct{ name, value string }{
+			{"currency", r.Currency},
+			{"bank name", r.Bank.Name},
+			{"bank address", r.Bank.Address},
+			{"bank postal code", r.Bank.PostalCode},
+			{"bank city", r.Bank.City},
+			{"bank country", r.Bank.Country},
+		}...)
+	}
+	for _, field := range required {
+		if field.value == "" {
+			return fmt.Errorf("%s is required for %s withdrawals", field.name, r.Type)
+		}
+	}
+	return nil
+}
+
+func (r BankWithdrawalRequest) params() map[string]string {
+	params := map[string]string{
+		"type":             string(r.Type),
+		"amount":           r.Amount.String(),
+		"account_currency": r.AccountCurrency,
+		"name":             r.Beneficiary.Name,
+		"iban":             r.Beneficiary.Iban,
+		"bic":              r.Beneficiary.Bic,
+		"address":          r.Beneficiary.Address,
+		"postal_code":      r.Beneficiary.PostalCode,
+		"city":             r.Beneficiary.City,
+		"country":          r.Beneficiary.Country,
+	}
+	if r.Type == InternationalWithdrawal {
+		params["currency"] = r.Currency
+		params["bank_name"] = r.Bank.Name
+		params["bank_address"] = r.Bank.Address
+		params["bank_postal_code"] = r.Bank.PostalCode
+		params["bank_city"] = r.Bank.City
+		params["bank_country"] = r.Bank.Country
+	}
+	if r.Comment != "" {
+		params["comment"] = r.Comment
+	}
+	return params
+}
+
+type V2OpenBankWithdrawalResponse struct {
+	WithdrawalId StringInt `json:"withdrawal_id"`
+}
+
+// POST https://www.bitstamp.net/api/v2/withdrawal/open/
+//
+// Like crypto withdrawals, bank withdrawals are never retried.
+func (c *HttpClient) V2OpenBankWithdrawal(request BankWithdrawalRequest) (response V2OpenBankWithdrawalResponse, err error) {
+	return c.V2OpenBankWithdrawalWithContext(context.Background(), request)
+}
+
+func (c *HttpClient) V2OpenBankWithdrawalWithContext(ctx context.Context, request BankWithdrawalRequest) (response V2OpenBankWithdrawalResponse, err error) {
+	if err = request.Validate(); err != nil {
+		return
+	}
+	err = c.authenticatedFormRequest(ctx, "V2OpenBankWithdrawal", &response, "POST", "/v2/withdrawal/open/", nil, request.params())
+	if err != nil {
+		err = fmt.Errorf("error opening %s withdrawal of %s %s: %w", request.Type, request.Amount, request.AccountCurrency, err)
+	}
+	return
+}
+
+type V2BankWithdrawalStatusResponse struct {
+	Status WithdrawalStatus `json:"status"`
+}
+
+// POST https://www.bitstamp.net/api/v2/withdrawal/status/
+func (c *HttpClient) V2BankWithdrawalStatus(withdrawalId int64) (response V2BankWithdrawalStatusResponse, err error) {
+	return c.V2BankWithdrawalStatusWithContext(context.Background(), withdrawalId)
+}
+
+func (c *HttpClient) V2BankWithdrawalStatusWithContext(ctx context.Context, withdrawalId int64) (response V2BankWithdrawalStatusResponse, err error) {
+	params := map[string]string{"id": strconv.FormatInt(withdrawalId, 10)}
+	err = c.authenticatedFormRequest(ctx, "V2BankWithdrawalStatus", &response, "POST", "/v2/withdrawal/status/", nil, params)
+	return
+}
+
+type V2CancelBankWithdrawalResponse struct {
+	Id              StringInt          `json:"id"`
+	Amount          decimal.Decimal    `json:"amount"`
+	Currency        string             `json:"currency"`
+	AccountCurrency string             `json:"account_currency"`
+	Type            BankWithdrawalType `json:"type"`
+}
+
+// POST https://www.bitstamp.net/api/v2/withdrawal/cancel/
+//
+// Only withdrawals which haven't been processed yet (see V2BankWithdrawalStatus) can be canceled.
+func (c *HttpClient) V2CancelBankWithdrawal(withdrawalId int64) (response V2CancelBankWithdrawalResponse, err error) {
+	return c.V2CancelBankWithdrawalWithContext(context.Background(), withdrawalId)
+}
+
+func (c *HttpClient) V2CancelBankWithdrawalWithContext(ctx context.Context, withdrawalId int64) (response V2CancelBankWithdrawalResponse, err error) {
+	params := map[string]string{"id": strconv.FormatInt(withdrawalId, 10)}
+	err = c.authenticatedFormRequest(ctx, "V2CancelBankWithdrawal", &response, "POST", "/v2/withdrawal/cancel/", nil, params)
+	return
+}
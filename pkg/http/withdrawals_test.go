@@ -0,0 +1,193 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCryptoWithdrawalRequest_Validate(t *testing.T) {
+	d := decimal.RequireFromString
+	tag := uint32(12345)
+	cases := []struct {
+		name    string
+		request CryptoWithdrawalRequest
+		err     string
+	}{
+		{"btc", CryptoWithdrawalRequest{Currency: "btc", Amount: d("0.1"), Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"}, ""},
+		{"xrp with tag", CryptoWithdrawalRequest{Currency: "XRP", Amount: d("10"), Address: "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", DestinationTag: &tag}, ""},
+		{"xlm with memo", CryptoWithdrawalRequest{Currency: "xlm", Amount: d("10"), Address: "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A", MemoId: "memo"}, ""},
+		{"missing currency", CryptoWithdrawalRequest{Amount: d("0.1"), Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"}, "currency is required"},
+		{"zero amount", CryptoWithdrawalRequest{Currency: "btc", Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"}, "amount must be positive"},
+		{"missing address", CryptoWithdrawalRequest{Currency: "btc", Amount: d("0.1")}, "address is required"},
+		{"tag on btc", CryptoWithdrawalRequest{Currency: "btc", Amount: d("0.1"), Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", DestinationTag: &tag}, "destination tag is not supported for btc"},
+		{"memo on xrp", CryptoWithdrawalRequest{Currency: "xrp", Amount: d("10"), Address: "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", MemoId: "memo"}, "memo id is not supported for xrp"},
+		{"third party without contact", CryptoWithdrawalRequest{Currency: "btc", Amount: d("0.1"), Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", ContactThirdparty: true}, "contact uuid is required"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestV2CryptoWithdrawal(t *testing.T) {
+	var path string
+	var form url.Values
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		_ = r.ParseForm()
+		path, form = r.URL.Path, r.PostForm
+		if form.Get("address") == "1BoatSLRHtKNngkdXEeobR76b53LETtpyT" {
+			return 400, `{"status": "error", "reason": {"address": ["Enter a valid address."]}}`
+		}
+		return 200, `{"id": 42}`
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+	tag := uint32(7)
+
+	resp, err := c.V2CryptoWithdrawal(CryptoWithdrawalRequest{Currency: "xrp", Amount: decimal.NewFromInt(25), Address: "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", DestinationTag: &tag, Network: "xrpl"})
+	assert.NoError(t, err)
+	assert.Equal(t, StringInt(42), resp.Id)
+	assert.Equal(t, "/v2/xrp_withdrawal/", path)
+	assert.Equal(t, url.Values{
+		"amount":          {"25"},
+		"address":         {"rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"},
+		"network":         {"xrpl"},
+		"destination_tag": {"7"},
+	}, form)
+
+	// well-formed, but rejected by the exchange
+	_, err = c.V2CryptoWithdrawal(CryptoWithdrawalRequest{Currency: "btc", Amount: decimal.NewFromInt(1), Address: "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"})
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Equal(t, "/v2/btc_withdrawal/", path)
+
+	// malformed, never sent
+	path = ""
+	_, err = c.V2CryptoWithdrawal(CryptoWithdrawalRequest{Currency: "btc", Amount: decimal.NewFromInt(1), Address: "bogus"})
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Empty(t, path)
+}
+
+func TestWaitForWithdrawal(t *testing.T) {
+	polls := 0
+	statuses := []string{`0`, `"1"`, `2`}
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		polls++
+		switch {
+		case polls == 1:
+			return 502, `Bad Gateway` // transient, keep polling
+		case polls == 2:
+			return 200, `[]` // not listed yet
+		default:
+			return 200, `[{"id": 42, "type": 1, "currency": "BTC", "amount": "0.1", "status": ` + statuses[polls-3] + `}]`
+		}
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+	withdrawal, err := c.WaitForWithdrawal(context.Background(), 42, time.Millisecond)
+	assert.NoError(t, err)
+	assert.Equal(t, WithdrawalFinished, withdrawal.Status)
+	assert.True(t, withdrawal.Status.Done())
+	assert.Equal(t, 5, polls)
+
+	failed := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		return 200, `[{"id": 42, "status": "Failed"}]`
+	})
+	c = NewHttpClient(UrlDomain(failed.URL), Credentials("key", "secret"))
+	withdrawal, err = c.WaitForWithdrawal(context.Background(), 42, time.Millisecond)
+	assert.ErrorIs(t, err, ErrWithdrawalFailed)
+	assert.Equal(t, WithdrawalFailed, withdrawal.Status)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	pending := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		return 200, `[{"id": 42, "status": 1}]`
+	})
+	c = NewHttpClient(UrlDomain(pending.URL), Credentials("key", "secret"))
+	_, err = c.WaitForWithdrawal(ctx, 42, time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = c.WaitForWithdrawal(context.Background(), 42, 0)
+	assert.ErrorContains(t, err, "invalid polling interval")
+}
+
+func TestV2BankWithdrawals(t *testing.T) {
+	var paths []string
+	var forms []url.Values
+	server := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		_ = r.ParseForm()
+		paths = append(paths, r.URL.Path)
+		forms = append(forms, r.PostForm)
+		switch r.URL.Path {
+		case "/v2/withdrawal/open/":
+			return 200, `{"withdrawal_id": "7"}`
+		case "/v2/withdrawal/status/":
+			return 200, `{"status": "In process"}`
+		default:
+			return 200, `{"id": 7, "amount": "100.00", "currency": "EUR", "account_currency": "EUR", "type": "sepa"}`
+		}
+	})
+	c := NewHttpClient(UrlDomain(server.URL), Credentials("key", "secret"))
+
+	request := BankWithdrawalRequest{
+		Type:            SepaWithdrawal,
+		Amount:          decimal.NewFromInt(100),
+		AccountCurrency: "EUR",
+		Beneficiary: BankWithdrawalBeneficiary{
+			Name: "Jane Doe", Iban: "SI56...", Bic: "LJBASI2X", Address: "Main St 1", PostalCode: "1000", City: "Ljubljana", Country: "SI",
+		},
+	}
+	opened, err := c.V2OpenBankWithdrawal(request)
+	assert.NoError(t, err)
+	assert.Equal(t, StringInt(7), opened.WithdrawalId)
+	assert.Equal(t, url.Values{
+		"type":             {"sepa"},
+		"amount":           {"100"},
+		"account_currency": {"EUR"},
+		"name":             {"Jane Doe"},
+		"iban":             {"SI56..."},
+		"bic":              {"LJBASI2X"},
+		"address":          {"Main St 1"},
+		"postal_code":      {"1000"},
+		"city":             {"Ljubljana"},
+		"country":          {"SI"},
+	}, forms[0])
+
+	status, err := c.V2BankWithdrawalStatus(7)
+	assert.NoError(t, err)
+	assert.Equal(t, WithdrawalInProcess, status.Status)
+
+	canceled, err := c.V2CancelBankWithdrawal(7)
+	assert.NoError(t, err)
+	assert.Equal(t, StringInt(7), canceled.Id)
+	assert.Equal(t, SepaWithdrawal, canceled.Type)
+	assert.Equal(t, "7", forms[2].Get("id"))
+	assert.Equal(t, []string{"/v2/withdrawal/open/", "/v2/withdrawal/status/", "/v2/withdrawal/cancel/"}, paths)
+
+	// international withdrawals need the bank's details as well, checked before anything is sent
+	request.Type = InternationalWithdrawal
+	request.Currency = "USD"
+	_, err = c.V2OpenBankWithdrawal(request)
+	assert.ErrorContains(t, err, "bank name is required for international withdrawals")
+	assert.Len(t, paths, 3)
+
+	// the beneficiary's address is a postal one
+	refusing := newSignedTestServer(t, "secret", func(r *http.Request) (int, string) {
+		return 400, `{"status": "error", "reason": {"address": ["This field is required."]}}`
+	})
+	c = NewHttpClient(UrlDomain(refusing.URL), Credentials("key", "secret"))
+	request.Type = SepaWithdrawal
+	_, err = c.V2OpenBankWithdrawal(request)
+	assert.ErrorContains(t, err, "This field is required.")
+	assert.NotErrorIs(t, err, ErrInvalidAddress)
+}